@@ -28,7 +28,7 @@ func NewMenuController(menuService *service.MenuService) *MenuController {
 // @Success 200 {object} []model.Menu "菜单详情"
 // @Router /menu/tree [get]
 func (m *MenuController) GetMenuTree(ctx *gin.Context) {
-	tree, err := m.MenuService.Tree()
+	tree, err := m.MenuService.Tree(ctx.Request.Context())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": err.Error()})
 		return
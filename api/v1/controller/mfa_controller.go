@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tier-up/internal/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAController 两步验证控制器
+type MFAController struct {
+	MFAService *service.MFAService
+}
+
+// MFALoginRequest 两步验证登录请求
+type MFALoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"` // 登录接口返回的 mfaToken
+	Code     string `json:"code" binding:"required"`     // 6 位验证码或恢复码
+}
+
+// MFACodeRequest 验证码请求
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"` // 6 位验证码
+}
+
+// MFADisableRequest 停用两步验证请求
+type MFADisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // 6 位验证码或恢复码
+}
+
+// NewMFAController 创建两步验证控制器
+func NewMFAController(mfaService *service.MFAService) *MFAController {
+	return &MFAController{
+		MFAService: mfaService,
+	}
+}
+
+// VerifyLogin 两步验证登录
+// @Summary 两步验证登录
+// @Description 提交登录接口返回的 mfaToken 和验证器中的验证码（或恢复码）获取令牌，验证码错误 5 次后需要重新登录
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body MFALoginRequest true "验证信息"
+// @Success 200 {object} map[string]interface{} "登录成功，返回token"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "验证码错误或验证已失效"
+// @Failure 429 {object} map[string]interface{} "失败次数过多，稍后再试"
+// @Router /login/mfa [post]
+func (c *MFAController) VerifyLogin(ctx *gin.Context) {
+	var req MFALoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	tokens, user, err := c.MFAService.VerifyLogin(ctx.Request.Context(), req.MFAToken, req.Code, client(ctx))
+	if loginBlocked(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, gin.H{"code": 401, "message": "登录失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "登录成功", "data": loginData(tokens, user)})
+}
+
+// Enroll 获取两步验证密钥
+// @Summary 获取两步验证密钥
+// @Description 生成 TOTP 密钥，uri 为验证器应用扫描的二维码内容，secret 用于手动输入；调用确认接口后生效
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "密钥和二维码内容"
+// @Failure 400 {object} map[string]interface{} "已启用两步验证"
+// @Failure 500 {object} map[string]interface{} "生成密钥失败"
+// @Router /user/mfa/totp [post]
+func (c *MFAController) Enroll(ctx *gin.Context) {
+	userIDValue, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+
+	enrollment, err := c.MFAService.Enroll(ctx.Request.Context(), userIDValue.(uint64))
+	if errors.Is(err, service.ErrMFAAlreadyEnabled) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "生成密钥失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "获取密钥成功", "data": enrollment})
+}
+
+// Confirm 启用两步验证
+// @Summary 启用两步验证
+// @Description 提交验证器中的验证码确认密钥，启用两步验证并返回恢复码，恢复码只显示这一次；
+// @Description 角色要求两步验证时，启用后调用 /token/refresh 获取不受限的令牌
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data body MFACodeRequest true "验证码"
+// @Success 200 {object} map[string]interface{} "启用成功，返回恢复码"
+// @Failure 400 {object} map[string]interface{} "验证码错误"
+// @Failure 500 {object} map[string]interface{} "启用失败"
+// @Router /user/mfa/totp/confirm [post]
+func (c *MFAController) Confirm(ctx *gin.Context) {
+	userIDValue, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	codes, err := c.MFAService.Confirm(ctx.Request.Context(), userIDValue.(uint64), req.Code)
+	if errors.Is(err, service.ErrMFAAlreadyEnabled) || errors.Is(err, service.ErrMFANotEnrolled) || errors.Is(err, service.ErrInvalidMFACode) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "启用两步验证失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "启用两步验证成功", "data": gin.H{"recoveryCodes": codes}})
+}
+
+// Disable 停用两步验证
+// @Summary 停用两步验证
+// @Description 提交密码和验证码（或恢复码）停用两步验证，角色要求两步验证时不能停用
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data body MFADisableRequest true "密码和验证码"
+// @Success 200 {object} map[string]interface{} "停用成功"
+// @Failure 400 {object} map[string]interface{} "密码或验证码错误"
+// @Failure 403 {object} map[string]interface{} "角色要求两步验证"
+// @Failure 500 {object} map[string]interface{} "停用失败"
+// @Router /user/mfa/totp [delete]
+func (c *MFAController) Disable(ctx *gin.Context) {
+	userIDValue, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+
+	var req MFADisableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	err := c.MFAService.Disable(ctx.Request.Context(), userIDValue.(uint64), req.Password, req.Code)
+	if errors.Is(err, service.ErrMFARequired) {
+		ctx.JSON(http.StatusForbidden, gin.H{"code": 403, "message": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrMFANotEnabled) || errors.Is(err, service.ErrWrongPassword) || errors.Is(err, service.ErrInvalidMFACode) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "停用两步验证失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "停用两步验证成功"})
+}
+
+// Reset 重置用户的两步验证
+// @Summary 重置用户的两步验证
+// @Description 清除用户的两步验证密钥和恢复码，用于用户丢失验证器和恢复码的情况
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} map[string]interface{} "重置成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 500 {object} map[string]interface{} "重置失败"
+// @Router /user/{id}/mfa [delete]
+func (c *MFAController) Reset(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	if err := c.MFAService.Reset(ctx.Request.Context(), userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "重置两步验证失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "重置两步验证成功"})
+}
@@ -39,7 +39,7 @@ func (c *RoleController) GetRoleByID(ctx *gin.Context) {
 		return
 	}
 
-	role, err := c.RoleService.GetRoleByID(uint(id))
+	role, err := c.RoleService.GetRoleByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取角色失败: " + err.Error()})
 		return
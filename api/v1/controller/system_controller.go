@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+	"tier-up/internal/route"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SystemController 系统控制器
+type SystemController struct {
+	Registry *route.Registry
+	DB       *gorm.DB
+}
+
+// NewSystemController 创建系统控制器
+func NewSystemController(registry *route.Registry, db *gorm.DB) *SystemController {
+	return &SystemController{
+		Registry: registry,
+		DB:       db,
+	}
+}
+
+// GetRoutes 获取路由清单
+// @Summary 获取路由清单
+// @Description 获取全部已注册路由，rbac=true 时只返回需要权限验证的路由，用于权限配置
+// @Tags System
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rbac query bool false "只返回需要权限验证的路由"
+// @Success 200 {object} map[string]interface{} "路由清单"
+// @Router /system/routes [get]
+func (c *SystemController) GetRoutes(ctx *gin.Context) {
+	routes := c.Registry.Routes()
+	if ctx.Query("rbac") == "true" {
+		filtered := make([]route.Route, 0, len(routes))
+		for _, r := range routes {
+			if r.RBAC {
+				filtered = append(filtered, r)
+			}
+		}
+		routes = filtered
+	}
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "获取路由成功", "data": routes})
+}
+
+// GetDBStats 获取数据库连接池状态
+// @Summary 获取数据库连接池状态
+// @Description 返回主库 sql.DBStats，wait_duration_ms 等待连接的累计时长，max_idle_closed 等为累计关闭的连接数
+// @Tags System
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "连接池状态"
+// @Router /system/db/stats [get]
+func (c *SystemController) GetDBStats(ctx *gin.Context) {
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取连接池失败: " + err.Error()})
+		return
+	}
+	stats := sqlDB.Stats()
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "获取连接池状态成功", "data": gin.H{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}})
+}
@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"tier-up/internal/app/middleware/jwt"
+	"tier-up/internal/app/model"
 	"tier-up/internal/app/service"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // UserController 用户控制器
@@ -16,7 +21,22 @@ type UserController struct {
 // PasswordRequest 密码更新请求
 type PasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6,max=100"`
+	NewPassword string `json:"new_password" binding:"required,max=100"` // 复杂度由密码策略校验
+}
+
+// ResetPasswordRequest 管理员重置密码请求
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,max=100"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// StatusRequest 用户状态请求
+type StatusRequest struct {
+	Status *int `json:"status" binding:"required"` // 1:正常, 其他值:禁用
 }
 
 // RoleRequest 角色请求
@@ -49,7 +69,12 @@ func (c *UserController) Register(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.UserService.Register(req)
+	user, err := c.UserService.Register(ctx.Request.Context(), req)
+	var policy *service.PasswordPolicyError
+	if errors.As(err, &policy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": policy.Error(), "data": policy.Violations})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "注册失败: " + err.Error()})
 		return
@@ -60,14 +85,17 @@ func (c *UserController) Register(ctx *gin.Context) {
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户登录获取令牌
+// @Description 用户登录获取令牌。已启用两步验证时返回 mfaRequired 和 mfaToken，再调用 /login/mfa 提交验证码获取令牌；
+// @Description mustChangePassword 为 true 时密码已过期，令牌只能用于修改密码和退出登录；
+// @Description mfaSetupRequired 为 true 时角色要求两步验证，令牌只能用于启用两步验证和退出登录
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param data body service.LoginRequest true "用户登录信息"
 // @Success 200 {object} map[string]interface{} "登录成功，返回token"
 // @Failure 400 {object} map[string]interface{} "参数错误"
-// @Failure 401 {object} map[string]interface{} "登录失败"
+// @Failure 401 {object} map[string]interface{} "用户名或密码错误"
+// @Failure 429 {object} map[string]interface{} "失败次数过多，稍后再试"
 // @Router /login [post]
 func (c *UserController) Login(ctx *gin.Context) {
 	var req service.LoginRequest
@@ -76,21 +104,86 @@ func (c *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, user, err := c.UserService.Login(req)
+	result, err := c.UserService.Login(ctx.Request.Context(), req, client(ctx))
+	if loginBlocked(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, gin.H{"code": 401, "message": "登录失败: " + err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "登录成功",
-		"data": gin.H{
-			"accessToken": accessToken,
-			// "refreshToken": refreshToken,
-			"user": user,
-		},
-	})
+	if result.MFAToken != "" {
+		ctx.JSON(http.StatusOK, gin.H{
+			"code":    0,
+			"message": "请输入两步验证码",
+			"data": gin.H{
+				"mfaRequired": true,
+				"mfaToken":    result.MFAToken,
+				"expiresIn":   result.MFAExpiresIn,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "登录成功", "data": loginData(result.Tokens, result.User)})
+}
+
+// loginData 登录成功返回的令牌和用户信息
+func loginData(tokens *jwt.TokenPair, user *model.User) gin.H {
+	return gin.H{
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"tokenType":    tokens.TokenType,
+		// 密码已过期，需要先调用 PUT /user/password 修改密码
+		"mustChangePassword": tokens.MustChangePassword,
+		// 角色要求两步验证，需要先启用两步验证，再刷新令牌
+		"mfaSetupRequired": tokens.MFASetupRequired,
+		"user":             user,
+	}
+}
+
+// loginBlocked 登录失败次数过多时返回 429 和 Retry-After
+func loginBlocked(ctx *gin.Context, err error) bool {
+	var blocked *service.LoginBlockedError
+	if !errors.As(err, &blocked) {
+		return false
+	}
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"code": 429, "message": blocked.Error()})
+	return true
+}
+
+// RefreshToken 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效；已使用的刷新令牌再次使用时吊销该会话的全部令牌
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body RefreshRequest true "刷新令牌"
+// @Success 200 {object} map[string]interface{} "刷新成功，返回新的令牌"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "刷新令牌无效"
+// @Router /token/refresh [post]
+func (c *UserController) RefreshToken(ctx *gin.Context) {
+	var req RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	tokens, err := c.UserService.RefreshToken(ctx.Request.Context(), req.RefreshToken, client(ctx))
+	if err != nil {
+		if errors.Is(err, jwt.ErrInvalidRefreshToken) || errors.Is(err, jwt.ErrRefreshTokenReused) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "刷新令牌失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "刷新成功", "data": tokens})
 }
 
 // GetUserInfo 获取用户信息
@@ -111,8 +204,9 @@ func (c *UserController) GetUserInfo(ctx *gin.Context) {
 		return
 	}
 
-	userID := userIDValue.(uint)
-	user, err := c.UserService.GetUserByID(userID)
+	// JWT 中间件写入的用户 ID 为 uint64
+	userID := uint(userIDValue.(uint64))
+	user, err := c.UserService.GetUserByID(ctx.Request.Context(), userID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取用户信息失败: " + err.Error()})
 		return
@@ -123,7 +217,7 @@ func (c *UserController) GetUserInfo(ctx *gin.Context) {
 
 // ChangePassword 修改密码
 // @Summary 修改密码
-// @Description 修改当前用户的密码
+// @Description 修改当前用户的密码，新密码需要符合密码策略，修改后已签发的令牌全部失效
 // @Tags User
 // @Accept json
 // @Produce json
@@ -141,7 +235,8 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	userID := userIDValue.(uint)
+	// JWT 中间件写入的用户 ID 为 uint64
+	userID := uint(userIDValue.(uint64))
 
 	var req PasswordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -149,7 +244,13 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.UserService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
+	err := c.UserService.ChangePassword(ctx.Request.Context(), userID, req.OldPassword, req.NewPassword)
+	var policy *service.PasswordPolicyError
+	if errors.As(err, &policy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": policy.Error(), "data": policy.Violations})
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "修改密码失败: " + err.Error()})
 		return
 	}
@@ -157,6 +258,46 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "修改密码成功"})
 }
 
+// ResetPassword 重置密码
+// @Summary 重置用户密码
+// @Description 管理员重置用户密码，新密码需要符合密码策略；已签发的令牌全部失效，用户下次登录需要先修改密码
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param data body ResetPasswordRequest true "新密码"
+// @Success 200 {object} map[string]interface{} "重置成功"
+// @Failure 400 {object} map[string]interface{} "参数错误或密码不符合策略"
+// @Failure 500 {object} map[string]interface{} "重置密码失败"
+// @Router /user/{id}/password [put]
+func (c *UserController) ResetPassword(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	var req ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	err = c.UserService.ResetPassword(ctx.Request.Context(), uint(userID), req.NewPassword)
+	var policy *service.PasswordPolicyError
+	if errors.As(err, &policy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": policy.Error(), "data": policy.Violations})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "重置密码失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "重置密码成功"})
+}
+
 // AssignRole 分配角色
 // @Summary 分配角色给用户
 // @Description 为指定用户分配角色
@@ -183,7 +324,7 @@ func (c *UserController) AssignRole(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.UserService.AssignRoleToUser(uint(userID), req.RoleID); err != nil {
+	if err := c.UserService.AssignRoleToUser(ctx.Request.Context(), uint(userID), req.RoleID); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "分配角色失败: " + err.Error()})
 		return
 	}
@@ -217,10 +358,224 @@ func (c *UserController) RemoveRole(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.UserService.RemoveRoleFromUser(uint(userID), req.RoleID); err != nil {
+	if err := c.UserService.RemoveRoleFromUser(ctx.Request.Context(), uint(userID), req.RoleID); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "移除角色失败: " + err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "移除角色成功"})
 }
+
+// Unlock 解除登录锁定
+// @Summary 解除用户的登录锁定
+// @Description 清除用户名的登录失败计数，解除延迟和锁定
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} map[string]interface{} "解除成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 500 {object} map[string]interface{} "解除锁定失败"
+// @Router /user/{id}/unlock [post]
+func (c *UserController) Unlock(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	if err := c.UserService.Unlock(ctx.Request.Context(), uint(userID)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "解除锁定失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "解除锁定成功"})
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 终止当前会话，会话的访问令牌和刷新令牌立即失效
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "退出成功"
+// @Failure 401 {object} map[string]interface{} "未认证"
+// @Failure 500 {object} map[string]interface{} "退出失败"
+// @Router /logout [post]
+func (c *UserController) Logout(ctx *gin.Context) {
+	value, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+
+	if err := c.UserService.Logout(ctx.Request.Context(), value.(*jwt.CustomClaims)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "退出失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "退出成功"})
+}
+
+// SetStatus 修改用户状态
+// @Summary 启用或禁用用户
+// @Description 修改用户状态，禁用后该用户已签发的令牌全部失效
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param data body StatusRequest true "用户状态"
+// @Success 200 {object} map[string]interface{} "修改成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 500 {object} map[string]interface{} "修改状态失败"
+// @Router /user/{id}/status [put]
+func (c *UserController) SetStatus(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	var req StatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := c.UserService.SetStatus(ctx.Request.Context(), uint(userID), *req.Status); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "修改状态失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "修改状态成功"})
+}
+
+// ListSessions 当前用户的会话
+// @Summary 我的登录会话
+// @Description 列出当前用户未退出且未过期的会话，current 为 true 的是当前请求所属的会话
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "会话列表"
+// @Failure 401 {object} map[string]interface{} "未认证"
+// @Failure 500 {object} map[string]interface{} "获取会话失败"
+// @Router /user/sessions [get]
+func (c *UserController) ListSessions(ctx *gin.Context) {
+	value, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+	claims := value.(*jwt.CustomClaims)
+
+	sessions, err := c.UserService.ListSessions(ctx.Request.Context(), uint(claims.UserID), claims.SessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取会话失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "获取会话成功", "data": sessions})
+}
+
+// DeleteSession 终止当前用户的会话
+// @Summary 终止我的会话
+// @Description 终止当前用户的指定会话，该会话的访问令牌和刷新令牌立即失效
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Success 200 {object} map[string]interface{} "终止成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 404 {object} map[string]interface{} "会话不存在"
+// @Failure 500 {object} map[string]interface{} "终止会话失败"
+// @Router /user/sessions/{id} [delete]
+func (c *UserController) DeleteSession(ctx *gin.Context) {
+	userIDValue, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "未认证"})
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的会话ID"})
+		return
+	}
+
+	c.revokeSession(ctx, uint(userIDValue.(uint64)), id)
+}
+
+// AdminListSessions 用户的会话
+// @Summary 用户的登录会话
+// @Description 列出指定用户未退出且未过期的会话
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} map[string]interface{} "会话列表"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 500 {object} map[string]interface{} "获取会话失败"
+// @Router /user/{id}/sessions [get]
+func (c *UserController) AdminListSessions(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	sessions, err := c.UserService.ListSessions(ctx.Request.Context(), uint(userID), "")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取会话失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "获取会话成功", "data": sessions})
+}
+
+// AdminDeleteSession 终止用户的会话
+// @Summary 终止用户的会话
+// @Description 终止指定用户的会话，该会话的访问令牌和刷新令牌立即失效
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param sid path int true "会话ID"
+// @Success 200 {object} map[string]interface{} "终止成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 404 {object} map[string]interface{} "会话不存在"
+// @Failure 500 {object} map[string]interface{} "终止会话失败"
+// @Router /user/{id}/sessions/{sid} [delete]
+func (c *UserController) AdminDeleteSession(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的用户ID"})
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("sid"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的会话ID"})
+		return
+	}
+
+	c.revokeSession(ctx, uint(userID), id)
+}
+
+func (c *UserController) revokeSession(ctx *gin.Context, userID uint, id uint64) {
+	if err := c.UserService.RevokeSession(ctx.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"code": 404, "message": "会话不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "终止会话失败: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "终止会话成功"})
+}
+
+// client 请求的客户端信息，记录到登录会话
+func client(ctx *gin.Context) jwt.Client {
+	return jwt.Client{UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+}
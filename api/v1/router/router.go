@@ -2,13 +2,20 @@ package router
 
 import (
 	"tier-up/api/v1/controller"
+	_ "tier-up/internal/app" // 注册模型
 	"tier-up/internal/app/middleware/auth"
+	"tier-up/internal/app/middleware/cors"
+	"tier-up/internal/app/middleware/idempotency"
 	"tier-up/internal/app/middleware/jwt"
-	"tier-up/internal/app/model"
+	"tier-up/internal/app/middleware/ratelimit"
+	"tier-up/internal/app/middleware/requestid"
 	"tier-up/internal/app/service"
+	"tier-up/internal/config"
 	"tier-up/internal/crud"
+	"tier-up/internal/openapi"
+	"tier-up/internal/route"
 
-	_ "tier-up/docs"
+	"tier-up/docs"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -19,56 +26,114 @@ import (
 
 func SetupDigRouter(r *gin.Engine, c *dig.Container) error {
 	return c.Invoke(func(
-		db *gorm.DB,
 		jwtService *jwt.JWTService,
+		idem *idempotency.Idempotency,
 		userService *service.UserService,
 		roleService *service.RoleService,
 		userController *controller.UserController,
 		roleController *controller.RoleController,
 		menuController *controller.MenuController,
-	) {
-		// 设置API路由组
-		api := r.Group("/api/v1")
+		systemController *controller.SystemController,
+		mfaController *controller.MFAController,
+		registry *route.Registry,
+		db *gorm.DB,
+		corsMiddleware *cors.CORS,
+		limiter *ratelimit.Limiter,
+		cfg config.Config,
+	) error {
+		// 客户端 IP 用于限流、登录失败计数和会话记录，只信任配置的代理转发的地址
+		if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+			return err
+		}
+		// 请求 ID，写入响应头并用于关联 SQL 日志
+		r.Use(requestid.Middleware())
+		// 跨域，需在路由之前处理预检请求
+		r.Use(corsMiddleware.Handler())
 
-		api.GET("/swagger/*any", gs.WrapHandler(swaggerFiles.Handler))
+		// 非对称签名的公钥，供其他服务验证令牌
+		r.GET("/.well-known/jwks.json", jwtService.JWKSHandler())
+
+		// 设置API路由组，路由信息记录到注册表
+		// 按客户端 IP 限流，RateLimit.Rate 为 0 时不限流
+		api := registry.Group(r.Group("/api/v1", limiter.Middleware()))
+
+		api.Gin().GET("/swagger/*any", gs.WrapHandler(swaggerFiles.Handler))
 		// 不需要认证的路由
 		{
 			// 用户认证
-			api.POST("/register", userController.Register)
-			api.POST("/login", userController.Login)
+			api.Tag("User").POST("/register", idem.Middleware(), userController.Register)
+			api.Tag("User").POST("/login", userController.Login)
+			api.Tag("User").POST("/login/mfa", mfaController.VerifyLogin)
+			api.Tag("User").POST("/token/refresh", userController.RefreshToken)
 		}
 
 		// 需要登录认证的路由
-		authGroup := api.Group("")
-		authGroup.Use(jwtService.JWTAuthMiddleware())
+		// 携带 Idempotency-Key 的创建请求重试时返回首次结果
+		// 密码已过期的令牌只能修改密码和退出登录，需要启用两步验证的令牌只能启用两步验证和退出登录
+		authGroup := api.Auth(
+			jwtService.JWTAuthMiddleware(),
+			jwtService.RestrictionGuard(jwt.Restrictions{
+				PasswordExpired: []string{"/api/v1/user/password", "/api/v1/logout"},
+				MFASetup:        []string{"/api/v1/user/mfa/totp", "/api/v1/user/mfa/totp/confirm", "/api/v1/logout"},
+			}),
+			idem.Middleware(),
+		)
 		{
 
 			// 需要权限验证的路由
-			rbacGroup := authGroup.Group("")
-			rbacGroup.Use(auth.AuthMiddleware())
+			rbacGroup := authGroup.RBAC(auth.AuthMiddleware())
 			{
+				// 已注册模型的 crud 路由
+				crud.Mount(crud.Groups{Public: api, Auth: authGroup, RBAC: rbacGroup}, db)
+
 				// 用户相关
-				crud.RegisterCrudRoutes[model.User, model.UserReq](authGroup, db)
+				authGroup.Tag("User").GET("/user/info", userController.GetUserInfo)
+				authGroup.Tag("User").PUT("/user/password", userController.ChangePassword)
+				authGroup.Tag("User").POST("/logout", userController.Logout)
+				authGroup.Tag("User").GET("/user/sessions", userController.ListSessions)
+				authGroup.Tag("User").DELETE("/user/sessions/:id", userController.DeleteSession)
 
-				authGroup.GET("/user/info", userController.GetUserInfo)
-				authGroup.PUT("/user/password", userController.ChangePassword)
+				// 两步验证
+				authGroup.Tag("User").POST("/user/mfa/totp", mfaController.Enroll)
+				authGroup.Tag("User").POST("/user/mfa/totp/confirm", mfaController.Confirm)
+				authGroup.Tag("User").DELETE("/user/mfa/totp", mfaController.Disable)
 
 				// 用户角色管理
-				rbacGroup.POST("/user/:id/role", userController.AssignRole)
-				rbacGroup.DELETE("/user/:id/role", userController.RemoveRole)
-
-				// 角色管理
-				crud.RegisterCrudRoutes[model.Role, model.RoleReq](rbacGroup, db)
+				rbacGroup.Tag("User").POST("/user/:id/role", userController.AssignRole)
+				rbacGroup.Tag("User").DELETE("/user/:id/role", userController.RemoveRole)
+				rbacGroup.Tag("User").PUT("/user/:id/status", userController.SetStatus)
+				rbacGroup.Tag("User").POST("/user/:id/unlock", userController.Unlock)
+				rbacGroup.Tag("User").PUT("/user/:id/password", userController.ResetPassword)
+				rbacGroup.Tag("User").GET("/user/:id/sessions", userController.AdminListSessions)
+				rbacGroup.Tag("User").DELETE("/user/:id/sessions/:sid", userController.AdminDeleteSession)
+				rbacGroup.Tag("User").DELETE("/user/:id/mfa", mfaController.Reset)
 
 				// 权限管理
-				rbacGroup.POST("/permission", roleController.AddPermission)
-				rbacGroup.DELETE("/permission", roleController.RemovePermission)
-				rbacGroup.GET("/role-permissions/:name", roleController.GetPermissions)
+				permission := rbacGroup.Tag("权限管理")
+				permission.POST("/permission", roleController.AddPermission)
+				permission.DELETE("/permission", roleController.RemovePermission)
+				permission.GET("/role-permissions/:name", roleController.GetPermissions)
 
 				// 菜单管理
-				crud.RegisterCrudRoutes[model.Menu, model.MenuReq](rbacGroup, db)
-				rbacGroup.GET("/menu/tree", menuController.GetMenuTree)
+				rbacGroup.Tag("Menu").GET("/menu/tree", menuController.GetMenuTree)
+
+				// 系统
+				rbacGroup.Tag("System").GET("/system/routes", systemController.GetRoutes)
+				rbacGroup.Tag("System").GET("/system/db/stats", systemController.GetDBStats)
 			}
 		}
+
+		// OpenAPI 3.1 文档，在全部路由注册后生成
+		doc, err := BuildDocument(registry)
+		if err != nil {
+			return err
+		}
+		api.Gin().GET("/openapi.json", doc.Handler())
+		return nil
 	})
 }
+
+// BuildDocument 根据路由注册表、已注册模型和 swag 注释生成 OpenAPI 文档，需在路由注册完成后调用
+func BuildDocument(registry *route.Registry) (*openapi.Document, error) {
+	return openapi.Build(registry, crud.Models(), docs.SwaggerInfo.ReadDoc())
+}
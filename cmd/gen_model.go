@@ -1,5 +1,93 @@
 package main
 
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	_ "tier-up/internal/app" // 注册模型
+	"tier-up/internal/config"
+	"tier-up/internal/crud"
+	"tier-up/internal/db"
+	"tier-up/internal/scaffold"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 根据已有的数据表生成模型、请求参数和查询代码
+// 在项目根目录执行:
+//
+//	go run ./cmd -tables products,orders -types numeric=float64,jsonb=datatypes.JSON
+//
+// 未指定 -dsn 时使用 config.yaml 中的数据库配置
 func main() {
+	dsn := flag.String("dsn", "", "连接字符串，默认读取 config.yaml")
+	driverName := flag.String("driver", "postgres", "指定 -dsn 时的数据库驱动 postgres、mysql、sqlserver、sqlite")
+	tables := flag.String("tables", "", "表名，逗号分隔，为空时生成全部表并跳过已有模型的表")
+	types := flag.String("types", "", "数据库类型映射，如 numeric=float64,jsonb=datatypes.JSON")
+	out := flag.String("out", "internal/app/model", "模型输出目录")
+	query := flag.String("query", scaffold.QueryDir, "查询代码输出目录，为空时不生成")
+	access := flag.String("access", scaffold.AccessRBAC, "crud 接口访问级别 rbac、auth、public")
+	register := flag.Bool("register", true, "在 internal/app/models.go 中注册模型")
+	force := flag.Bool("force", false, "覆盖已存在的文件")
+	flag.Parse()
+
+	typeMap := map[string]string{}
+	for _, item := range split(*types) {
+		dbType, goType, ok := strings.Cut(item, "=")
+		if !ok || dbType == "" || goType == "" {
+			log.Fatalf("类型映射格式错误: %s，应为 dbtype=gotype", item)
+		}
+		typeMap[dbType] = goType
+	}
+
+	var cfg config.Config
+	if *dsn == "" {
+		cfg = config.MustLoad()
+	} else {
+		cfg.DB.Driver, cfg.DB.DSN = *driverName, *dsn
+	}
+	conn, err := db.Open(cfg, &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		log.Fatalf("连接数据库失败: %v", err)
+	}
+
+	// 已注册的模型与数据表一起生成查询代码
+	var models []interface{}
+	for _, m := range crud.Models() {
+		models = append(models, m.New())
+	}
+	files, skipped, err := scaffold.GenerateFromDB(conn, scaffold.TableOptions{
+		Tables:   split(*tables),
+		Types:    typeMap,
+		ModelDir: *out,
+		QueryDir: *query,
+		Access:   *access,
+		Register: *register,
+		Models:   models,
+		Force:    *force,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, t := range skipped {
+		fmt.Println("跳过已有模型的表", t)
+	}
+	for _, f := range files {
+		fmt.Println("写入", f)
+	}
+}
 
+func split(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
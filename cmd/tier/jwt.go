@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func jwtCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "jwt",
+		Usage: "JWT 签名密钥",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "keygen",
+				Usage: "生成非对称签名私钥，添加到 JWT.Keys 后在 ActiveFrom 开始签名",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "type", Usage: "密钥类型 rsa（RS256）、ed25519（EdDSA）", Value: "ed25519"},
+					&cli.IntFlag{Name: "bits", Usage: "RSA 密钥长度", Value: 2048},
+					&cli.StringFlag{Name: "id", Usage: "kid，默认为当前时间"},
+					&cli.StringFlag{Name: "dir", Usage: "输出目录", Value: "keys"},
+				},
+				Action: func(c *cli.Context) error {
+					var key crypto.Signer
+					var err error
+					switch c.String("type") {
+					case "rsa":
+						key, err = rsa.GenerateKey(rand.Reader, c.Int("bits"))
+					case "ed25519":
+						_, key, err = ed25519.GenerateKey(rand.Reader)
+					default:
+						return fmt.Errorf("不支持的密钥类型 %s，可选 rsa、ed25519", c.String("type"))
+					}
+					if err != nil {
+						return err
+					}
+					der, err := x509.MarshalPKCS8PrivateKey(key)
+					if err != nil {
+						return err
+					}
+
+					id := c.String("id")
+					if id == "" {
+						id = time.Now().Format("20060102150405")
+					}
+					dir := c.String("dir")
+					if err := os.MkdirAll(dir, 0o700); err != nil {
+						return err
+					}
+					path := filepath.Join(dir, id+".pem")
+					f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					if err := pem.Encode(f, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+						return err
+					}
+
+					fmt.Printf("已生成 %s，添加到配置:\n", path)
+					fmt.Printf("JWT:\n  Keys:\n    - ID: %q\n      File: %q\n      ActiveFrom: %q\n", id, path, time.Now().UTC().Format(time.RFC3339))
+					return nil
+				},
+			},
+		},
+	}
+}
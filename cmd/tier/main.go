@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tier-up/api/v1/router"
+	_ "tier-up/internal/app" // 注册模型
+	"tier-up/internal/config"
+	"tier-up/internal/crud"
+	"tier-up/internal/di"
+	"tier-up/internal/openapi"
+	"tier-up/internal/route"
+	"tier-up/internal/scaffold"
+
+	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli/v2"
+)
+
+// tier 项目命令行工具
+// 在项目根目录执行: go run ./cmd/tier gen module Product --fields "name:string:required,price:float64,status:*int"
+func main() {
+	app := &cli.App{
+		Name:  "tier",
+		Usage: "Tier-Go 项目工具",
+		Commands: []*cli.Command{
+			{
+				Name:  "gen",
+				Usage: "代码生成",
+				Subcommands: []*cli.Command{
+					genModuleCommand(),
+					genQueryCommand(),
+					genTSCommand(),
+				},
+			},
+			migrateCommand(),
+			seedCommand(),
+			jwtCommand(),
+		},
+	}
+	if err := app.Run(flagsFirst(os.Args)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func genModuleCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "module",
+		Usage:     "生成模型、请求参数、服务、控制器，并注册模型、依赖注入和路由",
+		ArgsUsage: "<Name>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "fields",
+				Aliases:  []string{"f"},
+				Usage:    "字段定义 name:type[:required]，逗号分隔，类型可选 string、int、int64、uint、uint64、float64、bool、time，前加 * 表示可为空",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "comment",
+				Usage: "模块中文名，用于注释",
+			},
+			&cli.StringFlag{
+				Name:  "access",
+				Usage: "接口访问级别 rbac、auth、public",
+				Value: scaffold.AccessRBAC,
+			},
+			&cli.StringFlag{
+				Name:  "root",
+				Usage: "项目根目录",
+				Value: ".",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "覆盖已存在的文件",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("需要指定模块名，如 tier gen module Product --fields name:string", 1)
+			}
+			files, err := scaffold.Generate(scaffold.Options{
+				Name:    c.Args().First(),
+				Fields:  c.String("fields"),
+				Comment: c.String("comment"),
+				Access:  c.String("access"),
+				Root:    c.String("root"),
+				Force:   c.Bool("force"),
+			})
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				fmt.Println("写入", f)
+			}
+			fmt.Println("完成，执行 go run ./cmd/tier gen query 更新查询代码，swag init --parseDependency --output docs 更新手写接口文档")
+			return nil
+		},
+	}
+}
+
+func genQueryCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "query",
+		Usage: "为已注册的模型生成 gorm/gen 查询代码，新增或修改模型后重新执行",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "输出目录",
+				Value: scaffold.QueryDir,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var models []interface{}
+			for _, m := range crud.Models() {
+				models = append(models, m.New())
+			}
+			if err := scaffold.GenerateQuery(nil, c.String("out"), models...); err != nil {
+				return err
+			}
+			fmt.Println("写入", c.String("out"))
+			return nil
+		},
+	}
+}
+
+func genTSCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "ts",
+		Usage: "根据路由注册表和模型生成前端 TypeScript 类型和请求客户端",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "输出文件",
+				Value: "docs/client.ts",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// 只注册路由，不连接数据库
+			db, err := scaffold.OfflineDB()
+			if err != nil {
+				return err
+			}
+			gin.SetMode(gin.ReleaseMode)
+			container := di.BuildContainer(config.NewManager(".", config.Config{}), db)
+			if err := router.SetupDigRouter(gin.New(), container); err != nil {
+				return err
+			}
+			var doc *openapi.Document
+			if err := container.Invoke(func(registry *route.Registry) (err error) {
+				doc, err = router.BuildDocument(registry)
+				return err
+			}); err != nil {
+				return err
+			}
+			out := c.String("out")
+			if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(out, openapi.TypeScript(doc), 0o644); err != nil {
+				return err
+			}
+			fmt.Println("写入", out)
+			return nil
+		},
+	}
+}
+
+// flagsFirst urfave/cli 遇到第一个位置参数后不再解析选项
+// 将 gen module、migrate create 之后的位置参数移到选项之后，支持 tier gen module Product --fields ...
+func flagsFirst(args []string) []string {
+	start := -1
+	for i := 1; i+1 < len(args); i++ {
+		if args[i] == "gen" && args[i+1] == "module" {
+			start = i + 2
+			break
+		}
+	}
+	for i := 1; start < 0 && i+2 < len(args); i++ {
+		if args[i] == "migrate" && args[i+1] == "create" {
+			start = i + 2
+		}
+	}
+	if start < 0 {
+		return args
+	}
+	out := append([]string{}, args[:start]...)
+	var positional []string
+	for i := start; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(a, "-"):
+			out = append(out, a)
+			// 带值的选项，值为下一个参数
+			name := strings.TrimLeft(a, "-")
+			if !strings.Contains(name, "=") && name != "force" && name != "go" && name != "help" && name != "h" && i+1 < len(args) {
+				i++
+				out = append(out, args[i])
+			}
+		default:
+			positional = append(positional, a)
+		}
+	}
+	return append(out, positional...)
+}
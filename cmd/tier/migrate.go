@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"tier-up/internal/config"
+	"tier-up/internal/db"
+	"tier-up/internal/migrate"
+
+	"github.com/urfave/cli/v2"
+)
+
+// 迁移文件目录
+const migrationsDir = "internal/db/migrations"
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "版本化数据库迁移，使用 config.yaml 中的数据库配置",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "执行未执行的迁移",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "steps", Usage: "最多执行的数量，0 为全部"},
+				},
+				Action: func(c *cli.Context) error {
+					m, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					done, err := m.Up(c.Context, c.Int("steps"))
+					for _, mg := range done {
+						fmt.Printf("执行 %d_%s\n", mg.Version, mg.Name)
+					}
+					if err == nil && len(done) == 0 {
+						fmt.Println("没有需要执行的迁移")
+					}
+					return err
+				},
+			},
+			{
+				Name:  "down",
+				Usage: "回滚最近执行的迁移",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "steps", Usage: "回滚的数量", Value: 1},
+					&cli.BoolFlag{Name: "all", Usage: "回滚全部迁移"},
+				},
+				Action: func(c *cli.Context) error {
+					steps := c.Int("steps")
+					if c.Bool("all") {
+						steps = 0
+					} else if steps < 1 {
+						return cli.Exit("--steps 至少为 1，回滚全部使用 --all", 1)
+					}
+					m, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					done, err := m.Down(c.Context, steps)
+					for _, mg := range done {
+						fmt.Printf("回滚 %d_%s\n", mg.Version, mg.Name)
+					}
+					return err
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "查看迁移状态",
+				Action: func(c *cli.Context) error {
+					m, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					list, err := m.Status(c.Context)
+					if err != nil {
+						return err
+					}
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "VERSION\tNAME\tSOURCE\tSTATUS")
+					for _, s := range list {
+						status := "pending"
+						if s.AppliedAt != nil {
+							status = "applied " + s.AppliedAt.Format(time.DateTime)
+						}
+						if s.Missing {
+							status += " (代码中不存在)"
+						}
+						fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, s.Source, status)
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:      "create",
+				Usage:     "新建迁移文件，默认生成 up 和 down 两个 SQL 文件",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "go", Usage: "生成 Go 迁移"},
+					&cli.StringFlag{Name: "dir", Usage: "迁移目录", Value: migrationsDir},
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("需要指定迁移名称，如 tier migrate create add_user_phone", 1)
+					}
+					files, err := migrate.Create(c.String("dir"), c.Args().First(), c.Bool("go"), time.Now())
+					if err != nil {
+						return err
+					}
+					for _, f := range files {
+						fmt.Println("写入", f)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// newMigrator 连接数据库并加载已注册的迁移
+func newMigrator() (*migrate.Migrator, error) {
+	gdb, err := db.Open(config.MustLoad())
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	return migrate.NewMigrator(gdb)
+}
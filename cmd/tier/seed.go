@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"tier-up/internal/app/middleware/casbin"
+	"tier-up/internal/app/service"
+	"tier-up/internal/config"
+	"tier-up/internal/db"
+	"tier-up/internal/seed"
+
+	"github.com/urfave/cli/v2"
+)
+
+func seedCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "写入 seeds 目录中的角色、用户、菜单和权限策略，可重复执行",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "env", Usage: "环境，读取 seed.<env>.yaml，默认为 APP_ENV"},
+			&cli.StringFlag{Name: "dir", Usage: "seed 文件目录，默认为配置 Seed.Dir"},
+		},
+		Action: func(c *cli.Context) error {
+			cfg := config.MustLoad()
+			env, dir := cfg.Env, cfg.Seed.Dir
+			if c.IsSet("env") {
+				env = c.String("env")
+			}
+			if c.IsSet("dir") {
+				dir = c.String("dir")
+			}
+			data, err := seed.Load(dir, env)
+			if err != nil {
+				return err
+			}
+			gdb, err := db.Open(cfg)
+			if err != nil {
+				return fmt.Errorf("连接数据库失败: %w", err)
+			}
+			// Casbin 从工作目录读取模型文件，在项目根目录执行
+			// 生产环境的用户密码必须符合 Password 配置的策略
+			passwords := service.NewPasswordPolicy(cfg, gdb)
+			res, err := seed.Apply(c.Context, gdb, casbin.InitCasbin(gdb), passwords, data, config.Config{Env: env}.IsProd())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("环境 %s: %s\n", env, res)
+			return nil
+		},
+	}
+}
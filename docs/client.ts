@@ -0,0 +1,447 @@
+// Code generated by tier gen ts. DO NOT EDIT.
+// 由 go run ./cmd/tier gen ts 根据路由注册表和模型生成
+
+/** 统一响应结构，code 为 0 表示成功 */
+export interface ApiResponse<T = unknown> {
+  code: number
+  message: string
+  data?: T
+}
+
+export interface ClientOptions {
+  /** 接口前缀，如 http://localhost:88/api/v1 */
+  baseURL?: string
+  /** 返回当前的访问令牌（登录返回的 accessToken），请求时以 Bearer 方式放入 Authorization 头 */
+  token?: () => string | null | undefined
+  headers?: Record<string, string>
+  fetch?: typeof fetch
+}
+
+export type Query = Record<string, string | number | boolean | null | undefined>
+
+/** HTTP 状态码非 2xx 时抛出 */
+export class ApiError extends Error {
+  status: number
+  body: ApiResponse | undefined
+
+  constructor(status: number, body: ApiResponse | undefined) {
+    super(body?.message ?? `HTTP ${status}`)
+    this.status = status
+    this.body = body
+  }
+}
+
+function createRequest(options: ClientOptions) {
+  const doFetch = options.fetch ?? fetch
+  return async <T>(method: string, path: string, query?: Query, body?: unknown, init?: RequestInit): Promise<T> => {
+    let url = (options.baseURL ?? '') + path
+    if (query) {
+      const params = new URLSearchParams()
+      for (const [key, value] of Object.entries(query)) {
+        if (value !== undefined && value !== null) params.append(key, String(value))
+      }
+      const qs = params.toString()
+      if (qs) url += '?' + qs
+    }
+    const headers: Record<string, string> = { ...options.headers }
+    const token = options.token?.()
+    if (token) headers['Authorization'] = 'Bearer ' + token
+    if (body !== undefined) headers['Content-Type'] = 'application/json'
+    const res = await doFetch(url, {
+      ...init,
+      method,
+      headers: { ...headers, ...(init?.headers as Record<string, string> | undefined) },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    const text = await res.text()
+    const data = text ? JSON.parse(text) : undefined
+    if (!res.ok) throw new ApiError(res.status, data)
+    return data as T
+  }
+}
+
+export interface LoginRequest {
+  password: string
+  username: string
+}
+
+export interface MFACodeRequest {
+  /** 6 位验证码 */
+  code: string
+}
+
+export interface MFADisableRequest {
+  /** 6 位验证码或恢复码 */
+  code: string
+  password: string
+}
+
+export interface MFALoginRequest {
+  /** 6 位验证码或恢复码 */
+  code: string
+  /** 登录接口返回的 mfaToken */
+  mfaToken: string
+}
+
+export interface Menu {
+  children?: Menu[]
+  code?: string
+  /** 组件路径 */
+  component?: string
+  created_at?: string
+  /** icon图标 */
+  icon?: string
+  id?: number
+  name?: string
+  /** 备注 */
+  note?: string
+  parent_id?: number | null
+  /** api路径 */
+  path?: string
+  /** 显示顺序 */
+  sort?: number
+  /** 状态:1正常 2禁用 */
+  status?: number | null
+  type?: number
+  updated_at?: string
+}
+
+export interface MenuReq {
+  code?: string
+  component?: string
+  icon?: string
+  name?: string
+  note?: string
+  parent_id?: number | null
+  path: string
+  sort?: number | null
+  status?: number | null
+  type: number
+}
+
+export interface MenuResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: Menu
+  message: string
+}
+
+export interface PasswordRequest {
+  /** 复杂度由密码策略校验 */
+  new_password: string
+  old_password: string
+}
+
+export interface PermissionRequest {
+  method: string
+  path: string
+  role: string
+}
+
+export interface RefreshRequest {
+  refreshToken: string
+}
+
+export interface RegisterRequest {
+  email: string
+  nickname?: string
+  /** 复杂度由密码策略校验 */
+  password: string
+  phone?: string
+  username: string
+}
+
+export interface ResetPasswordRequest {
+  new_password: string
+}
+
+export interface Role {
+  created_at?: string
+  description?: string
+  display_name?: string
+  id?: number
+  name?: string
+  require_mfa?: boolean
+  updated_at?: string
+}
+
+export interface RolePageResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: {
+    data?: Role[]
+    limit?: number
+    page?: number
+    total?: number
+  }
+  message: string
+}
+
+export interface RoleReq {
+  description?: string
+  display_name?: string
+  name?: string
+  require_mfa?: boolean
+}
+
+export interface RoleRequest {
+  role_id: number
+}
+
+export interface RoleResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: Role
+  message: string
+}
+
+export interface StatusRequest {
+  /** 1:正常, 其他值:禁用 */
+  status: number
+}
+
+export interface User {
+  avatar?: string
+  created_at?: string
+  email?: string
+  id?: number
+  mfa_enabled_at?: string | null
+  nickname?: string
+  password_expires_at?: string | null
+  phone?: string
+  roles?: Role[]
+  status?: number
+  updated_at?: string
+  username?: string
+}
+
+export interface UserPageResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: {
+    data?: User[]
+    limit?: number
+    page?: number
+    total?: number
+  }
+  message: string
+}
+
+export interface UserReq {
+  avatar?: string
+  email?: string
+  nickname?: string
+  phone?: string
+  status?: number
+  username?: string
+}
+
+export interface UserResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: User
+  message: string
+}
+
+export interface UserRole {
+  role_id?: number
+  user_id?: number
+}
+
+export interface UserRolePageResponse {
+  /** 0 表示成功 */
+  code: number
+  data?: {
+    data?: UserRole[]
+    limit?: number
+    page?: number
+    total?: number
+  }
+  message: string
+}
+
+export function createClient(options: ClientOptions = {}) {
+  const request = createRequest(options)
+  return {
+    /** 用户登录 */
+    login: (body: LoginRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/login', undefined, body, init),
+    /** 两步验证登录 */
+    verifyLogin: (body: MFALoginRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/login/mfa', undefined, body, init),
+    /** 退出登录 */
+    logout: (init?: RequestInit) =>
+      request<ApiResponse>('POST', '/logout', undefined, undefined, init),
+    /** 创建 Menu */
+    menuCreate: (body: MenuReq, init?: RequestInit) =>
+      request<MenuResponse>('POST', '/menu/create', undefined, body, init),
+    /** 删除 Menu */
+    menuDelete: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/menu/delete/${encodeURIComponent(String(id))}`, undefined, undefined, init),
+    /** 获取菜单树 */
+    getMenuTree: (init?: RequestInit) =>
+      request<ApiResponse<Menu[]>>('GET', '/menu/tree', undefined, undefined, init),
+    /** 更新 Menu */
+    menuUpdate: (id: number, body: MenuReq, init?: RequestInit) =>
+      request<MenuResponse>('PUT', `/menu/update/${encodeURIComponent(String(id))}`, undefined, body, init),
+    /** 移除权限 */
+    removePermission: (body: PermissionRequest, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', '/permission', undefined, body, init),
+    /** 添加权限 */
+    addPermission: (body: PermissionRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/permission', undefined, body, init),
+    /** 用户注册 */
+    register: (body: RegisterRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/register', undefined, body, init),
+    /** 获取角色权限 */
+    getPermissions: (name: string, init?: RequestInit) =>
+      request<ApiResponse>('GET', `/role-permissions/${encodeURIComponent(String(name))}`, undefined, undefined, init),
+    /** 创建 Role */
+    roleCreate: (body: RoleReq, init?: RequestInit) =>
+      request<RoleResponse>('POST', '/role/create', undefined, body, init),
+    /** 删除 Role */
+    roleDelete: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/role/delete/${encodeURIComponent(String(id))}`, undefined, undefined, init),
+    /** 分页查询 Role */
+    rolePage: (query?: {
+      /** 页码，从1开始 */
+      page?: number
+      /** 每页数量 */
+      limit?: number
+      /** 排序字段，逗号分隔，- 前缀表示倒序，如 -created_at */
+      sort?: string
+      /** 按 id 过滤，支持 id__like 等操作 */
+      id?: string
+      /** 按 created_at 过滤，支持 created_at__like 等操作 */
+      created_at?: string
+      /** 按 updated_at 过滤，支持 updated_at__like 等操作 */
+      updated_at?: string
+      /** 按 name 过滤，支持 name__like 等操作 */
+      name?: string
+      /** 按 display_name 过滤，支持 display_name__like 等操作 */
+      display_name?: string
+      /** 按 description 过滤，支持 description__like 等操作 */
+      description?: string
+      /** 按 require_mfa 过滤，支持 require_mfa__like 等操作 */
+      require_mfa?: string
+    } & Query, init?: RequestInit) =>
+      request<RolePageResponse>('GET', '/role/page', query, undefined, init),
+    /** 更新 Role */
+    roleUpdate: (id: number, body: RoleReq, init?: RequestInit) =>
+      request<RoleResponse>('PUT', `/role/update/${encodeURIComponent(String(id))}`, undefined, body, init),
+    /** 获取数据库连接池状态 */
+    getDBStats: (init?: RequestInit) =>
+      request<ApiResponse>('GET', '/system/db/stats', undefined, undefined, init),
+    /** 获取路由清单 */
+    getRoutes: (query?: {
+      /** 只返回需要权限验证的路由 */
+      rbac?: boolean
+    } & Query, init?: RequestInit) =>
+      request<ApiResponse>('GET', '/system/routes', query, undefined, init),
+    /** 刷新令牌 */
+    refreshToken: (body: RefreshRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/token/refresh', undefined, body, init),
+    /** 分页查询 UserRole */
+    userRolePage: (query?: {
+      /** 页码，从1开始 */
+      page?: number
+      /** 每页数量 */
+      limit?: number
+      /** 排序字段，逗号分隔，- 前缀表示倒序，如 -created_at */
+      sort?: string
+      /** 按 user_id 过滤，支持 user_id__like 等操作 */
+      user_id?: string
+      /** 按 role_id 过滤，支持 role_id__like 等操作 */
+      role_id?: string
+    } & Query, init?: RequestInit) =>
+      request<UserRolePageResponse>('GET', '/user-role/page', query, undefined, init),
+    /** 创建 User */
+    userCreate: (body: UserReq, init?: RequestInit) =>
+      request<UserResponse>('POST', '/user/create', undefined, body, init),
+    /** 删除 User */
+    userDelete: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/user/delete/${encodeURIComponent(String(id))}`, undefined, undefined, init),
+    /** 获取当前用户信息 */
+    getUserInfo: (init?: RequestInit) =>
+      request<ApiResponse>('GET', '/user/info', undefined, undefined, init),
+    /** 停用两步验证 */
+    disable: (body: MFADisableRequest, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', '/user/mfa/totp', undefined, body, init),
+    /** 获取两步验证密钥 */
+    enroll: (init?: RequestInit) =>
+      request<ApiResponse>('POST', '/user/mfa/totp', undefined, undefined, init),
+    /** 启用两步验证 */
+    confirm: (body: MFACodeRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', '/user/mfa/totp/confirm', undefined, body, init),
+    /** 分页查询 User */
+    userPage: (query?: {
+      /** 页码，从1开始 */
+      page?: number
+      /** 每页数量 */
+      limit?: number
+      /** 排序字段，逗号分隔，- 前缀表示倒序，如 -created_at */
+      sort?: string
+      /** 按 id 过滤，支持 id__like 等操作 */
+      id?: string
+      /** 按 created_at 过滤，支持 created_at__like 等操作 */
+      created_at?: string
+      /** 按 updated_at 过滤，支持 updated_at__like 等操作 */
+      updated_at?: string
+      /** 按 username 过滤，支持 username__like 等操作 */
+      username?: string
+      /** 按 nickname 过滤，支持 nickname__like 等操作 */
+      nickname?: string
+      /** 按 email 过滤，支持 email__like 等操作 */
+      email?: string
+      /** 按 phone 过滤，支持 phone__like 等操作 */
+      phone?: string
+      /** 按 avatar 过滤，支持 avatar__like 等操作 */
+      avatar?: string
+      /** 按 status 过滤，支持 status__like 等操作 */
+      status?: string
+      /** 按 password_expires_at 过滤，支持 password_expires_at__like 等操作 */
+      password_expires_at?: string
+      /** 按 totp_enabled_at 过滤，支持 totp_enabled_at__like 等操作 */
+      totp_enabled_at?: string
+    } & Query, init?: RequestInit) =>
+      request<UserPageResponse>('GET', '/user/page', query, undefined, init),
+    /** 修改密码 */
+    changePassword: (body: PasswordRequest, init?: RequestInit) =>
+      request<ApiResponse>('PUT', '/user/password', undefined, body, init),
+    /** 我的登录会话 */
+    listSessions: (init?: RequestInit) =>
+      request<ApiResponse>('GET', '/user/sessions', undefined, undefined, init),
+    /** 终止我的会话 */
+    deleteSession: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/user/sessions/${encodeURIComponent(String(id))}`, undefined, undefined, init),
+    /** 更新 User */
+    userUpdate: (id: number, body: UserReq, init?: RequestInit) =>
+      request<UserResponse>('PUT', `/user/update/${encodeURIComponent(String(id))}`, undefined, body, init),
+    /** 重置用户的两步验证 */
+    reset: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/user/${encodeURIComponent(String(id))}/mfa`, undefined, undefined, init),
+    /** 重置用户密码 */
+    resetPassword: (id: number, body: ResetPasswordRequest, init?: RequestInit) =>
+      request<ApiResponse>('PUT', `/user/${encodeURIComponent(String(id))}/password`, undefined, body, init),
+    /** 移除用户的角色 */
+    removeRole: (id: number, body: RoleRequest, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/user/${encodeURIComponent(String(id))}/role`, undefined, body, init),
+    /** 分配角色给用户 */
+    assignRole: (id: number, body: RoleRequest, init?: RequestInit) =>
+      request<ApiResponse>('POST', `/user/${encodeURIComponent(String(id))}/role`, undefined, body, init),
+    /** 用户的登录会话 */
+    adminListSessions: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('GET', `/user/${encodeURIComponent(String(id))}/sessions`, undefined, undefined, init),
+    /** 终止用户的会话 */
+    adminDeleteSession: (id: number, sid: number, init?: RequestInit) =>
+      request<ApiResponse>('DELETE', `/user/${encodeURIComponent(String(id))}/sessions/${encodeURIComponent(String(sid))}`, undefined, undefined, init),
+    /** 启用或禁用用户 */
+    setStatus: (id: number, body: StatusRequest, init?: RequestInit) =>
+      request<ApiResponse>('PUT', `/user/${encodeURIComponent(String(id))}/status`, undefined, body, init),
+    /** 解除用户的登录锁定 */
+    unlock: (id: number, init?: RequestInit) =>
+      request<ApiResponse>('POST', `/user/${encodeURIComponent(String(id))}/unlock`, undefined, undefined, init),
+  }
+}
+
+export type Client = ReturnType<typeof createClient>
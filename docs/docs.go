@@ -21,7 +21,7 @@ const docTemplate = `{
     "paths": {
         "/login": {
             "post": {
-                "description": "用户登录获取令牌",
+                "description": "用户登录获取令牌。已启用两步验证时返回 mfaRequired 和 mfaToken，再调用 /login/mfa 提交验证码获取令牌；\nmustChangePassword 为 true 时密码已过期，令牌只能用于修改密码和退出登录；\nmfaSetupRequired 为 true 时角色要求两步验证，令牌只能用于启用两步验证和退出登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,7 +59,14 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "登录失败",
+                        "description": "用户名或密码错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "失败次数过多，稍后再试",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -68,9 +75,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/create": {
+        "/login/mfa": {
             "post": {
-                "description": "创建 Menu",
+                "description": "提交登录接口返回的 mfaToken 和验证器中的验证码（或恢复码）获取令牌，验证码错误 5 次后需要重新登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,48 +85,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "User"
                 ],
-                "summary": "创建 Menu",
+                "summary": "两步验证登录",
                 "parameters": [
                     {
-                        "description": "Menu 数据",
+                        "description": "验证信息",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.MenuReq"
+                            "$ref": "#/definitions/controller.MFALoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "登录成功，返回token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "验证码错误或验证已失效",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "失败次数过多，稍后再试",
                         "schema": {
-                            "$ref": "#/definitions/crud.MenuResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/menu/delete/:id": {
-            "delete": {
-                "description": "删除 Menu",
-                "consumes": [
-                    "application/json"
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "终止当前会话，会话的访问令牌和刷新令牌立即失效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "User"
                 ],
-                "summary": "删除 Menu",
+                "summary": "退出登录",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "退出成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未认证",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "退出失败",
                         "schema": {
-                            "$ref": "#/definitions/crud.MenuResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
@@ -155,40 +201,6 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/update/:id": {
-            "put": {
-                "description": "更新 Menu",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Menu"
-                ],
-                "summary": "更新 Menu",
-                "parameters": [
-                    {
-                        "description": "Menu 数据",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.MenuReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/crud.MenuResponse"
-                        }
-                    }
-                }
-            }
-        },
         "/permission": {
             "post": {
                 "security": [
@@ -396,9 +408,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/create": {
-            "post": {
-                "description": "创建 Role",
+        "/role/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据ID获取角色详情",
                 "consumes": [
                     "application/json"
                 ],
@@ -408,77 +425,49 @@ const docTemplate = `{
                 "tags": [
                     "Role"
                 ],
-                "summary": "创建 Role",
+                "summary": "获取角色详情",
                 "parameters": [
                     {
-                        "description": "Role 数据",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RoleReq"
-                        }
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "角色详情",
                         "schema": {
-                            "$ref": "#/definitions/crud.RoleResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/role/delete/:id": {
-            "delete": {
-                "description": "删除 Role",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Role"
-                ],
-                "summary": "删除 Role",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "无效的角色ID",
                         "schema": {
-                            "$ref": "#/definitions/crud.RoleResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/role/page": {
-            "get": {
-                "description": "分页查询 Role",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Role"
-                ],
-                "summary": "分页查询 Role",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "获取角色失败",
                         "schema": {
-                            "$ref": "#/definitions/crud.RolePageResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/role/update/:id": {
-            "put": {
-                "description": "更新 Role",
+        "/system/db/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回主库 sql.DBStats，wait_duration_ms 等待连接的累计时长，max_idle_closed 等为累计关闭的连接数",
                 "consumes": [
                     "application/json"
                 ],
@@ -486,38 +475,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
-                ],
-                "summary": "更新 Role",
-                "parameters": [
-                    {
-                        "description": "Role 数据",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RoleReq"
-                        }
-                    }
+                    "System"
                 ],
+                "summary": "获取数据库连接池状态",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "连接池状态",
                         "schema": {
-                            "$ref": "#/definitions/crud.RoleResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/role/{id}": {
+        "/system/routes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据ID获取角色详情",
+                "description": "获取全部已注册路由，rbac=true 时只返回需要权限验证的路由，用于权限配置",
                 "consumes": [
                     "application/json"
                 ],
@@ -525,35 +504,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "System"
                 ],
-                "summary": "获取角色详情",
+                "summary": "获取路由清单",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "角色ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "只返回需要权限验证的路由",
+                        "name": "rbac",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "角色详情",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "无效的角色ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "获取角色失败",
+                        "description": "路由清单",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -562,9 +526,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/create": {
+        "/token/refresh": {
             "post": {
-                "description": "创建 User",
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效；已使用的刷新令牌再次使用时吊销该会话的全部令牌",
                 "consumes": [
                     "application/json"
                 ],
@@ -574,46 +538,38 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "创建 User",
+                "summary": "刷新令牌",
                 "parameters": [
                     {
-                        "description": "User 数据",
+                        "description": "刷新令牌",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserReq"
+                            "$ref": "#/definitions/controller.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "刷新成功，返回新的令牌",
                         "schema": {
-                            "$ref": "#/definitions/crud.UserResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/user/delete/:id": {
-            "delete": {
-                "description": "删除 User",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User"
-                ],
-                "summary": "删除 User",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "刷新令牌无效",
                         "schema": {
-                            "$ref": "#/definitions/crud.UserResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
@@ -662,37 +618,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/page": {
-            "get": {
-                "description": "分页查询 User",
-                "consumes": [
-                    "application/json"
+        "/user/mfa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "生成 TOTP 密钥，uri 为验证器应用扫描的二维码内容，secret 用于手动输入；调用确认接口后生效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "分页查询 User",
+                "summary": "获取两步验证密钥",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "密钥和二维码内容",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "已启用两步验证",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "生成密钥失败",
                         "schema": {
-                            "$ref": "#/definitions/crud.UserPageResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/user/password": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改当前用户的密码",
+                "description": "提交密码和验证码（或恢复码）停用两步验证，角色要求两步验证时不能停用",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,42 +673,42 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "修改密码",
+                "summary": "停用两步验证",
                 "parameters": [
                     {
-                        "description": "密码信息",
+                        "description": "密码和验证码",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.PasswordRequest"
+                            "$ref": "#/definitions/controller.MFADisableRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "修改成功",
+                        "description": "停用成功",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "参数错误",
+                        "description": "密码或验证码错误",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "未认证",
+                    "403": {
+                        "description": "角色要求两步验证",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "修改密码失败",
+                        "description": "停用失败",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -746,11 +717,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/update/:id": {
-            "put": {
-                "description": "更新 User",
-                "consumes": [
-                    "application/json"
+        "/user/mfa/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "提交验证器中的验证码确认密钥，启用两步验证并返回恢复码，恢复码只显示这一次；\n角色要求两步验证时，启用后调用 /token/refresh 获取不受限的令牌",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -758,36 +734,550 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "更新 User",
+                "summary": "启用两步验证",
                 "parameters": [
                     {
-                        "description": "User 数据",
+                        "description": "验证码",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "启用成功，返回恢复码",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "验证码错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "启用失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改当前用户的密码，新密码需要符合密码策略，修改后已签发的令牌全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "description": "密码信息",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserReq"
+                            "$ref": "#/definitions/controller.PasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未认证",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "修改密码失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出当前用户未退出且未过期的会话，current 为 true 的是当前请求所属的会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "我的登录会话",
+                "responses": {
+                    "200": {
+                        "description": "会话列表",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未认证",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "获取会话失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "终止当前用户的指定会话，该会话的访问令牌和刷新令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "终止我的会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "会话ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "终止成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "会话不存在",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "终止会话失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/mfa": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "清除用户的两步验证密钥和恢复码，用于用户丢失验证器和恢复码的情况",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "重置用户的两步验证",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重置成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "重置失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
+                }
+            }
+        },
+        "/user/{id}/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员重置用户密码，新密码需要符合密码策略；已签发的令牌全部失效，用户下次登录需要先修改密码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "重置用户密码",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新密码",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重置成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或密码不符合策略",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "重置密码失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/role": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "为指定用户分配角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "分配角色给用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色信息",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "分配成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "分配角色失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "从指定用户移除角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "移除用户的角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色信息",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "移除成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "移除角色失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出指定用户未退出且未过期的会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "用户的登录会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "会话列表",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "获取会话失败",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/sessions/{sid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "终止指定用户的会话，该会话的访问令牌和刷新令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "终止用户的会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "会话ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "终止成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "会话不存在",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "终止会话失败",
                         "schema": {
-                            "$ref": "#/definitions/crud.UserResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/user/{id}/role": {
-            "post": {
+        "/user/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "为指定用户分配角色",
+                "description": "修改用户状态，禁用后该用户已签发的令牌全部失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -797,7 +1287,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "分配角色给用户",
+                "summary": "启用或禁用用户",
                 "parameters": [
                     {
                         "type": "integer",
@@ -807,18 +1297,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "角色信息",
+                        "description": "用户状态",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.RoleRequest"
+                            "$ref": "#/definitions/controller.StatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "分配成功",
+                        "description": "修改成功",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -832,31 +1322,30 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "分配角色失败",
+                        "description": "修改状态失败",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/user/{id}/unlock": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "从指定用户移除角色",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "清除用户名的登录失败计数，解除延迟和锁定",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "移除用户的角色",
+                "summary": "解除用户的登录锁定",
                 "parameters": [
                     {
                         "type": "integer",
@@ -864,20 +1353,11 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "角色信息",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controller.RoleRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "移除成功",
+                        "description": "解除成功",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -891,7 +1371,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "移除角色失败",
+                        "description": "解除锁定失败",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -902,135 +1382,111 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "controller.PasswordRequest": {
+        "controller.MFACodeRequest": {
             "type": "object",
             "required": [
-                "new_password",
-                "old_password"
+                "code"
             ],
             "properties": {
-                "new_password": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 6
-                },
-                "old_password": {
+                "code": {
+                    "description": "6 位验证码",
                     "type": "string"
                 }
             }
         },
-        "controller.RoleRequest": {
+        "controller.MFADisableRequest": {
             "type": "object",
             "required": [
-                "role_id"
+                "code",
+                "password"
             ],
             "properties": {
-                "role_id": {
-                    "type": "integer"
+                "code": {
+                    "description": "6 位验证码或恢复码",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "crud.MenuResponse": {
+        "controller.MFALoginRequest": {
             "type": "object",
+            "required": [
+                "code",
+                "mfaToken"
+            ],
             "properties": {
                 "code": {
-                    "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/model.Menu"
+                    "description": "6 位验证码或恢复码",
+                    "type": "string"
                 },
-                "message": {
+                "mfaToken": {
+                    "description": "登录接口返回的 mfaToken",
                     "type": "string"
                 }
             }
         },
-        "crud.RolePageResponse": {
+        "controller.PasswordRequest": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "data": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Role"
-                            }
-                        },
-                        "limit": {
-                            "type": "integer"
-                        },
-                        "page": {
-                            "type": "integer"
-                        },
-                        "total": {
-                            "type": "integer"
-                        }
-                    }
+                "new_password": {
+                    "description": "复杂度由密码策略校验",
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "message": {
+                "old_password": {
                     "type": "string"
                 }
             }
         },
-        "crud.RoleResponse": {
+        "controller.RefreshRequest": {
             "type": "object",
+            "required": [
+                "refreshToken"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/model.Role"
-                },
-                "message": {
+                "refreshToken": {
                     "type": "string"
                 }
             }
         },
-        "crud.UserPageResponse": {
+        "controller.ResetPasswordRequest": {
             "type": "object",
+            "required": [
+                "new_password"
+            ],
             "properties": {
-                "code": {
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "controller.RoleRequest": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
                     "type": "integer"
-                },
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "data": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
-                        },
-                        "limit": {
-                            "type": "integer"
-                        },
-                        "page": {
-                            "type": "integer"
-                        },
-                        "total": {
-                            "type": "integer"
-                        }
-                    }
-                },
-                "message": {
-                    "type": "string"
                 }
             }
         },
-        "crud.UserResponse": {
+        "controller.StatusRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "code": {
+                "status": {
+                    "description": "1:正常, 其他值:禁用",
                     "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/model.User"
-                },
-                "message": {
-                    "type": "string"
                 }
             }
         },
@@ -1044,7 +1500,7 @@ const docTemplate = `{
                     }
                 },
                 "code": {
-                    "type": "integer"
+                    "type": "string"
                 },
                 "component": {
                     "type": "string"
@@ -1064,9 +1520,6 @@ const docTemplate = `{
                 "note": {
                     "type": "string"
                 },
-                "parent": {
-                    "$ref": "#/definitions/model.Menu"
-                },
                 "parent_id": {
                     "description": "允许为空的父ID",
                     "type": "integer"
@@ -1088,146 +1541,6 @@ const docTemplate = `{
                 }
             }
         },
-        "model.MenuReq": {
-            "type": "object",
-            "required": [
-                "component",
-                "path",
-                "sort",
-                "type"
-            ],
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "component": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "note": {
-                    "type": "string"
-                },
-                "parent_id": {
-                    "type": "integer"
-                },
-                "path": {
-                    "type": "string"
-                },
-                "sort": {
-                    "type": "integer"
-                },
-                "status": {
-                    "type": "integer"
-                },
-                "type": {
-                    "type": "integer"
-                }
-            }
-        },
-        "model.Role": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.RoleReq": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.User": {
-            "type": "object",
-            "properties": {
-                "avatar": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "nickname": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "roles": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Role"
-                    }
-                },
-                "status": {
-                    "description": "1:正常, 0:禁用",
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.UserReq": {
-            "type": "object",
-            "properties": {
-                "avatar": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "nickname": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
         "service.LoginRequest": {
             "type": "object",
             "required": [
@@ -1277,9 +1590,9 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "password": {
+                    "description": "复杂度由密码策略校验",
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 6
+                    "maxLength": 100
                 },
                 "phone": {
                     "type": "string"
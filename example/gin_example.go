@@ -3,8 +3,7 @@ package main
 func main() {
 
 	// 初始化配置
-	/* config := config.Config{}
-	config.InitConfig()
+	/* config := config.MustLoad()
 	// 初始化数据库
 	sqlDB, gormDB := db.InitDB(config)
 	defer sqlDB.Close()
@@ -34,7 +34,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		cs := casbin.GetInstance()
 
 		// 使用用户ID作为主体进行权限检查
-		sub := strconv.Itoa(int(userID.(uint)))
+		sub := strconv.FormatUint(userID.(uint64), 10)
 		ok, err := cs.Enforce(sub, obj, act)
 
 		if err != nil {
@@ -0,0 +1,102 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"tier-up/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS 跨域中间件，配置可以在运行中更新
+type CORS struct {
+	rules atomic.Pointer[rules]
+}
+
+// rules 由配置预先拼接的响应头
+type rules struct {
+	config.CORSConfig
+	methods, headers, expose, maxAge string
+}
+
+// New 创建跨域中间件
+func New(c config.CORSConfig) *CORS {
+	cors := &CORS{}
+	cors.Update(c)
+	return cors
+}
+
+// Update 更新配置，之后的请求使用新配置
+func (cors *CORS) Update(c config.CORSConfig) {
+	cors.rules.Store(&rules{
+		CORSConfig: c,
+		methods:    strings.Join(c.AllowMethods, ", "),
+		headers:    strings.Join(c.AllowHeaders, ", "),
+		expose:     strings.Join(c.ExposeHeaders, ", "),
+		maxAge:     strconv.Itoa(int(c.MaxAge.Seconds())),
+	})
+}
+
+// Middleware 按配置处理跨域请求，AllowOrigins 为空时不处理
+func Middleware(c config.CORSConfig) gin.HandlerFunc {
+	return New(c).Handler()
+}
+
+// Handler 返回 gin 中间件
+func (cors *CORS) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c := cors.rules.Load()
+		origin := ctx.GetHeader("Origin")
+		if origin == "" || len(c.AllowOrigins) == 0 {
+			ctx.Next()
+			return
+		}
+		allowed := allowOrigin(c.AllowOrigins, origin)
+		if allowed == "" {
+			if ctx.Request.Method == http.MethodOptions {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		h := ctx.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", allowed)
+		h.Add("Vary", "Origin")
+		if c.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if c.expose != "" {
+			h.Set("Access-Control-Expose-Headers", c.expose)
+		}
+
+		// 预检请求
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", c.methods)
+			h.Set("Access-Control-Allow-Headers", c.headers)
+			if c.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", c.maxAge)
+			}
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// allowOrigin 返回 Access-Control-Allow-Origin 的值，不允许时为空
+func allowOrigin(origins []string, origin string) string {
+	for _, o := range origins {
+		if o == "*" {
+			return "*"
+		}
+		if strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"tier-up/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// HeaderKey 客户端携带的幂等键请求头
+const HeaderKey = "Idempotency-Key"
+
+// storeTimeout 请求结束后写入存储的超时时间
+const storeTimeout = 5 * time.Second
+
+// Idempotency 幂等请求服务
+type Idempotency struct {
+	Store Store
+	TTL   time.Duration
+	Lease time.Duration
+}
+
+// NewIdempotency 根据配置创建幂等服务
+func NewIdempotency(cfg config.Config, db *gorm.DB) *Idempotency {
+	var store Store
+	switch cfg.Idempotency.Store {
+	case "db":
+		store = NewDBStore(db)
+	default:
+		store = NewMemoryStore()
+	}
+	return &Idempotency{Store: store, TTL: cfg.Idempotency.TTL, Lease: cfg.Idempotency.Lease}
+}
+
+// bodyWriter 记录响应内容
+type bodyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware 幂等中间件
+// 仅处理携带 Idempotency-Key 的 POST 请求，key 按用户隔离
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idemKey := c.GetHeader(HeaderKey)
+		if idemKey == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(idemKey) > 128 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "Idempotency-Key 过长"})
+			c.Abort()
+			return
+		}
+
+		// 请求体摘要，同一个key不允许用于不同请求
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "读取请求失败"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		userID, _ := c.Get("userID")
+		key := fmt.Sprintf("%v:%s:%s:%s", userID, c.Request.Method, c.FullPath(), idemKey)
+
+		record, acquired, err := i.Store.Begin(c.Request.Context(), key, fingerprint, i.Lease)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "幂等检查失败: " + err.Error()})
+			c.Abort()
+			return
+		}
+
+		if !acquired {
+			switch {
+			case record.Fingerprint != fingerprint:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"code": 422, "message": "Idempotency-Key 已用于其他请求"})
+			case record.Status != StatusCompleted:
+				c.JSON(http.StatusConflict, gin.H{"code": 409, "message": "相同请求正在处理中"})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.StatusCode, record.ContentType, record.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+		defer func() {
+			// 处理过程中panic时释放key
+			if r := recover(); r != nil {
+				i.release(c, key)
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// 服务端错误不保存，允许客户端重试
+		if writer.Status() >= http.StatusInternalServerError {
+			i.release(c, key)
+			return
+		}
+		ctx, cancel := storeContext(c)
+		defer cancel()
+		if err := i.Store.Complete(ctx, key, i.TTL, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			// 未保存的key在租约到期后可以重新处理
+			slog.ErrorContext(ctx, "保存幂等响应失败", "key", key, "error", err)
+		}
+	}
+}
+
+// release 释放key，失败时key在租约到期后同样可以重新占用
+func (i *Idempotency) release(c *gin.Context, key string) {
+	ctx, cancel := storeContext(c)
+	defer cancel()
+	if err := i.Store.Release(ctx, key); err != nil {
+		slog.ErrorContext(ctx, "释放幂等键失败", "key", key, "error", err)
+	}
+}
+
+// storeContext 客户端断开连接会取消请求的 context，写入存储使用不会被取消的 context
+func storeContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(c.Request.Context()), storeTimeout)
+}
@@ -0,0 +1,178 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"tier-up/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+)
+
+// Record 已保存的请求记录
+type Record struct {
+	Key         string
+	Fingerprint string
+	Status      string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// Store 幂等记录存储
+type Store interface {
+	// Begin 占用key，lease 内未完成的key可以被重新占用；成功返回 true，key已存在时返回已有记录
+	Begin(ctx context.Context, key, fingerprint string, lease time.Duration) (*Record, bool, error)
+	// Complete 保存响应，保留 ttl
+	Complete(ctx context.Context, key string, ttl time.Duration, statusCode int, contentType string, body []byte) error
+	// Release 释放key，允许客户端重试
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore 内存存储，仅适用于单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*Record
+	lastSweep time.Time
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*Record)}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint string, lease time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.ExpiresAt) {
+		copied := *r
+		return &copied, false, nil
+	}
+	s.records[key] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      StatusProcessing,
+		ExpiresAt:   now.Add(lease),
+	}
+	return nil, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, ttl time.Duration, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[key]
+	if !ok {
+		return errors.New("幂等记录不存在")
+	}
+	r.Status = StatusCompleted
+	r.StatusCode = statusCode
+	r.ContentType = contentType
+	r.Body = body
+	r.ExpiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweep 每分钟清理一次过期记录
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for k, r := range s.records {
+		if !now.Before(r.ExpiresAt) {
+			delete(s.records, k)
+		}
+	}
+}
+
+// DBStore 数据库存储，多实例部署时共享
+type DBStore struct {
+	DB *gorm.DB
+}
+
+// NewDBStore 创建数据库存储
+func NewDBStore(db *gorm.DB) *DBStore {
+	return &DBStore{DB: db}
+}
+
+func (s *DBStore) Begin(ctx context.Context, key, fingerprint string, lease time.Duration) (*Record, bool, error) {
+	var existing *Record
+	acquired := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// 清理已过期的同名记录，包括租约到期仍未完成的请求
+		if err := tx.Where("idempotency_key = ? AND expires_at <= ?", key, now).Delete(&model.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		row := model.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      StatusProcessing,
+			ExpiresAt:   now.Add(lease),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			acquired = true
+			return nil
+		}
+
+		var found model.IdempotencyKey
+		if err := tx.Where("idempotency_key = ?", key).First(&found).Error; err != nil {
+			return err
+		}
+		existing = &Record{
+			Key:         found.Key,
+			Fingerprint: found.Fingerprint,
+			Status:      found.Status,
+			StatusCode:  found.StatusCode,
+			ContentType: found.ContentType,
+			Body:        found.Body,
+			ExpiresAt:   found.ExpiresAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, acquired, nil
+}
+
+func (s *DBStore) Complete(ctx context.Context, key string, ttl time.Duration, statusCode int, contentType string, body []byte) error {
+	return s.DB.WithContext(ctx).Model(&model.IdempotencyKey{}).Where("idempotency_key = ?", key).Updates(map[string]interface{}{
+		"status":       StatusCompleted,
+		"expires_at":   time.Now().Add(ttl),
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	}).Error
+}
+
+func (s *DBStore) Release(ctx context.Context, key string) error {
+	return s.DB.WithContext(ctx).Where("idempotency_key = ?", key).Delete(&model.IdempotencyKey{}).Error
+}
@@ -2,63 +2,138 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"tier-up/internal/app/model"
+	"tier-up/internal/config"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
-// JWTConfig JWT配置
-type JWTConfig struct {
-	SecretKey     string
-	TokenExpireIn time.Duration
-}
-
-// 默认配置
-var defaultConfig = JWTConfig{
-	SecretKey:     "tier_up_secret_key",
-	TokenExpireIn: time.Hour * 24, // 1天
-}
-
-// CustomClaims 自定义JWT声明
+// CustomClaims 自定义JWT声明，ID 为 jti，用于吊销单个令牌
 type CustomClaims struct {
-	UserID   uint64 `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint64 `json:"user_id"`
+	Username  string `json:"username"`
+	SessionID string `json:"sid,omitempty"` // 登录会话，即刷新令牌的 FamilyID
+	// PasswordExpired 密码已过期或被管理员重置，MFASetup 角色要求两步验证但尚未启用
+	// 两者为 true 时只能访问 RestrictionGuard 允许的接口
+	PasswordExpired bool `json:"pwd_expired,omitempty"`
+	MFASetup        bool `json:"mfa_setup,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // JWTService JWT服务
 type JWTService struct {
-	Config JWTConfig
+	Config      config.JWTConfig
+	DB          *gorm.DB         // 保存刷新令牌
+	Revocations *RevocationStore // 已吊销的访问令牌
+	mu          sync.RWMutex
+	keys        *keySet // 非对称签名密钥
+	touches     sessionTouches
+
+	// maxAccessTTL 运行以来访问令牌的最长有效期，有效期热更新缩短后已签发的令牌仍按原有效期过期
+	maxAccessTTL time.Duration
+}
+
+// NewJWTService 创建JWT服务，密钥、签发者等来自 JWT 配置
+func NewJWTService(cfg config.Config, db *gorm.DB) (*JWTService, error) {
+	keys, err := loadKeys(cfg.JWT.Keys)
+	if err != nil {
+		return nil, err
+	}
+	s := &JWTService{Config: cfg.JWT, DB: db, Revocations: NewRevocationStore(db), keys: keys, maxAccessTTL: cfg.JWT.AccessTTL}
+	s.registerCallbacks()
+	return s, nil
+}
+
+// SetKeys 重新读取非对称签名密钥，用于添加新密钥或删除已停用的密钥，失败时保留当前密钥
+func (s *JWTService) SetKeys(list []config.JWTKey) error {
+	keys, err := loadKeys(list)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	return nil
+}
+
+// JWKS 签名密钥的公钥，供其他服务验证令牌
+func (s *JWTService) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys.jwks()
 }
 
-// NewJWTService 创建JWT服务
-func NewJWTService() *JWTService {
-	return &JWTService{Config: defaultConfig}
+// SetTTL 修改访问令牌和刷新令牌的有效期，只影响之后签发的令牌
+func (s *JWTService) SetTTL(access, refresh time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Config.AccessTTL = access
+	s.Config.RefreshTTL = refresh
+	s.maxAccessTTL = max(s.maxAccessTTL, access)
 }
 
-// GenerateToken 生成JWT令牌
-func (s *JWTService) GenerateToken(userID uint64, username string) (string, error) {
+func (s *JWTService) accessTTL() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Config.AccessTTL
+}
+
+// GenerateToken 生成JWT令牌，sessionID 为登录会话
+// 密码已过期或需要启用两步验证时令牌受限，判断两步验证需要预加载 user.Roles
+func (s *JWTService) GenerateToken(user *model.User, sessionID string) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", err
+	}
 	// 创建自定义声明
+	now := time.Now()
 	claims := &CustomClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:          user.ID,
+		Username:        user.Username,
+		SessionID:       sessionID,
+		PasswordExpired: user.PasswordExpired(now),
+		MFASetup:        user.MFARequired() && !user.MFAEnabled(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.Config.TokenExpireIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "tier_up",
-			Subject:   username,
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.Config.Issuer,
+			Subject:   user.Username,
 		},
 	}
+	if s.Config.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.Config.Audience}
+	}
+
+	// 有已生效的非对称密钥时使用该密钥签名，并在头部写入 kid
+	s.mu.RLock()
+	key := s.keys.active(now)
+	s.mu.RUnlock()
+	if key != nil {
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.id
+		return token.SignedString(key.private)
+	}
+
+	if s.Config.Secret == "" {
+		return "", errors.New("没有可用的 JWT 签名密钥")
+	}
 
 	// 创建JWT令牌
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// 签名令牌
-	tokenString, err := token.SignedString([]byte(s.Config.SecretKey))
+	tokenString, err := token.SignedString([]byte(s.Config.Secret))
 	if err != nil {
 		return "", err
 	}
@@ -66,12 +141,43 @@ func (s *JWTService) GenerateToken(userID uint64, username string) (string, erro
 	return tokenString, nil
 }
 
+// verifyKey 按令牌头部的 kid 和 alg 选择验证密钥
+// 带 kid 的令牌使用对应的公钥，不带 kid 的令牌使用 HS256 密钥，兼容切换到非对称签名之前签发的令牌
+func (s *JWTService) verifyKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if token.Method != jwt.SigningMethodHS256 || s.Config.Secret == "" {
+			return nil, errors.New("令牌缺少 kid")
+		}
+		return []byte(s.Config.Secret), nil
+	}
+	s.mu.RLock()
+	key, ok := s.keys.byID[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未知的密钥 %s", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("密钥 %s 不能用于 %s", kid, token.Method.Alg())
+	}
+	return key.private.Public(), nil
+}
+
 // ParseToken 解析JWT令牌
 func (s *JWTService) ParseToken(tokenString string) (*CustomClaims, error) {
-	// 解析令牌
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.Config.SecretKey), nil
-	})
+	// 解析令牌，只接受 HS256、RS256 和 EdDSA，并校验签发者、接收方和有效期
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithLeeway(s.Config.ClockSkew),
+		jwt.WithIssuedAt(),
+	}
+	if s.Config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.Config.Issuer))
+	}
+	if s.Config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(s.Config.Audience))
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, s.verifyKey, opts...)
 
 	if err != nil {
 		return nil, err
@@ -112,10 +218,57 @@ func (s *JWTService) JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 退出登录、修改密码或禁用用户后令牌失效
+		if s.Revocations.IsRevoked(c.Request.Context(), claims) {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "令牌已失效，请重新登录"})
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存储到上下文中
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("claims", claims)
+		s.touch(c.Request.Context(), claims.SessionID)
 
 		c.Next()
 	}
 }
+
+// Restrictions 受限令牌允许访问的接口，路径为完整的路由路径
+type Restrictions struct {
+	PasswordExpired []string // 密码已过期
+	MFASetup        []string // 需要启用两步验证
+}
+
+// RestrictionGuard 令牌受限时只允许访问 Restrictions 中的接口，同时受多种限制时允许访问其中任一列表的接口
+// 需要放在 JWTAuthMiddleware 之后
+func (s *JWTService) RestrictionGuard(r Restrictions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("claims")
+		claims, ok := value.(*CustomClaims)
+		if !ok || !claims.PasswordExpired && !claims.MFASetup {
+			c.Next()
+			return
+		}
+		path := c.FullPath()
+		if claims.PasswordExpired && slices.Contains(r.PasswordExpired, path) || claims.MFASetup && slices.Contains(r.MFASetup, path) {
+			c.Next()
+			return
+		}
+		message := "请先启用两步验证"
+		if claims.PasswordExpired {
+			message = "密码已过期，请先修改密码"
+		}
+		c.JSON(http.StatusForbidden, gin.H{"code": 403, "message": message})
+		c.Abort()
+	}
+}
+
+// JWKSHandler 发布公钥，路径为 /.well-known/jwks.json
+func (s *JWTService) JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, s.JWKS())
+	}
+}
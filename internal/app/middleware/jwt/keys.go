@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"tier-up/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey 非对称签名密钥
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	private    crypto.Signer
+	activeFrom time.Time
+}
+
+// keySet 按 ActiveFrom 排序的密钥
+type keySet struct {
+	keys []*signingKey
+	byID map[string]*signingKey
+}
+
+// loadKeys 读取配置的私钥文件
+func loadKeys(list []config.JWTKey) (*keySet, error) {
+	ks := &keySet{byID: map[string]*signingKey{}}
+	for _, k := range list {
+		b, err := os.ReadFile(k.File)
+		if err != nil {
+			return nil, fmt.Errorf("读取 JWT 密钥 %s 失败: %w", k.ID, err)
+		}
+		private, err := ParsePrivateKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("解析 JWT 密钥 %s 失败: %w", k.ID, err)
+		}
+		key := &signingKey{id: k.ID, private: private, activeFrom: k.ActiveFrom}
+		switch private.(type) {
+		case *rsa.PrivateKey:
+			key.method = jwt.SigningMethodRS256
+		case ed25519.PrivateKey:
+			key.method = jwt.SigningMethodEdDSA
+		}
+		ks.keys = append(ks.keys, key)
+		ks.byID[k.ID] = key
+	}
+	sort.SliceStable(ks.keys, func(i, j int) bool { return ks.keys[i].activeFrom.Before(ks.keys[j].activeFrom) })
+	return ks, nil
+}
+
+// active 当前用于签名的密钥，即已生效的密钥中 ActiveFrom 最晚的，没有时返回 nil
+func (ks *keySet) active(now time.Time) *signingKey {
+	var key *signingKey
+	for _, k := range ks.keys {
+		if k.activeFrom.After(now) {
+			break
+		}
+		key = k
+	}
+	return key
+}
+
+// ParsePrivateKey 解析 PEM 格式的 RSA 或 Ed25519 私钥
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("不是 PEM 格式")
+	}
+	var key any
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = k
+	} else if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("RSA 密钥长度不能小于 2048 位")
+		}
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("不支持的密钥类型 %T，可选 RSA、Ed25519", key)
+}
+
+// JWK JSON Web Key 公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // OKP 曲线
+	X   string `json:"x,omitempty"`   // OKP 公钥
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// jwks 全部密钥的公钥，包括尚未生效和已被替换的密钥
+func (ks *keySet) jwks() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range ks.keys {
+		jwk := JWK{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+		switch pub := k.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
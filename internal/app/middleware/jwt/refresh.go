@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/opaque"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidRefreshToken 刷新令牌不存在、已过期或已吊销
+	ErrInvalidRefreshToken = errors.New("无效的刷新令牌")
+	// ErrRefreshTokenReused 已使用的刷新令牌再次使用，同一会话的令牌已全部吊销
+	ErrRefreshTokenReused = errors.New("刷新令牌已被使用，请重新登录")
+)
+
+// TokenPair 登录或刷新后返回的令牌
+type TokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"` // 访问令牌有效期，秒
+	TokenType    string `json:"tokenType"`
+	// MustChangePassword 密码已过期，访问令牌只能用于修改密码
+	MustChangePassword bool `json:"mustChangePassword"`
+	// MFASetupRequired 角色要求两步验证但尚未启用，访问令牌只能用于启用两步验证，启用后刷新令牌
+	MFASetupRequired bool `json:"mfaSetupRequired"`
+}
+
+// IssueTokens 登录成功后创建会话，签发访问令牌和刷新令牌
+func (s *JWTService) IssueTokens(ctx context.Context, user *model.User, client Client) (*TokenPair, error) {
+	family, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	var refresh string
+	err = repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, s.DB)
+		var expiresAt time.Time
+		refresh, expiresAt, err = s.createRefreshToken(tx, user.ID, family)
+		if err != nil {
+			return err
+		}
+		return createSession(tx, user.ID, family, client, expiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.pair(user, family, refresh)
+}
+
+// Refresh 使用刷新令牌换取新的令牌，旧的刷新令牌随即失效，并更新会话的最近使用时间
+// 已使用的令牌再次出现说明令牌可能泄露，终止该会话
+func (s *JWTService) Refresh(ctx context.Context, token string, client Client) (*TokenPair, error) {
+	var user model.User
+	var family, refresh string
+	reused := false
+	err := repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, s.DB)
+		var rt model.RefreshToken
+		err := tx.Where("token_hash = ?", opaque.Hash(token)).First(&rt).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if rt.RevokedAt != nil || !time.Now().Before(rt.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		// 条件更新保证并发请求中只有一个能完成轮换
+		now := time.Now()
+		res := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", rt.ID).
+			Update("used_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			reused = true
+			return s.revokeSession(tx, rt.UserID, rt.FamilyID)
+		}
+
+		if err := tx.Preload("Roles").First(&user, rt.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if user.Status != 1 {
+			return ErrInvalidRefreshToken
+		}
+		family = rt.FamilyID
+		var expiresAt time.Time
+		refresh, expiresAt, err = s.createRefreshToken(tx, rt.UserID, rt.FamilyID)
+		if err != nil {
+			return err
+		}
+		return touchSession(tx, family, client, expiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		slog.WarnContext(ctx, "刷新令牌被重复使用，已终止该会话", "ip", client.IP)
+		return nil, ErrRefreshTokenReused
+	}
+	return s.pair(&user, family, refresh)
+}
+
+// createRefreshToken 生成刷新令牌并保存摘要，返回令牌和过期时间
+func (s *JWTService) createRefreshToken(tx *gorm.DB, userID uint64, family string) (string, time.Time, error) {
+	// 256 位随机值
+	token, err := opaque.New(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	s.mu.RLock()
+	ttl := s.Config.RefreshTTL
+	s.mu.RUnlock()
+	row := model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: opaque.Hash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tx.Create(&row).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	return token, row.ExpiresAt, nil
+}
+
+func (s *JWTService) pair(user *model.User, family, refresh string) (*TokenPair, error) {
+	access, err := s.GenerateToken(user, family)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:        access,
+		RefreshToken:       refresh,
+		ExpiresIn:          int64(s.accessTTL().Seconds()),
+		TokenType:          "Bearer",
+		MustChangePassword: user.PasswordExpired(time.Now()),
+		MFASetupRequired:   user.MFARequired() && !user.MFAEnabled(),
+	}, nil
+}
+
+// randomID 会话 ID 和 jti，32 位十六进制
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,328 @@
+package jwt
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// revocationSyncInterval 从数据库同步其他实例吊销记录的间隔
+	revocationSyncInterval = 5 * time.Second
+	// revocationSyncOverlap 每次同步重新读取上次同步之前这段时间吊销的记录
+	// 吊销时间在事务提交前确定，提交较晚的记录和实例之间的时钟误差都在这个范围内
+	revocationSyncOverlap = time.Minute
+)
+
+// RevocationStore 访问令牌吊销记录，保存在数据库并缓存在内存
+// 校验令牌只读内存，本实例的吊销立即生效，其他实例的吊销在同步后生效
+type RevocationStore struct {
+	DB *gorm.DB
+
+	mu       sync.Mutex
+	tokens   map[string]time.Time // jti -> 令牌过期时间
+	sessions map[string]time.Time // 会话 -> 记录过期时间
+	synced   time.Time            // 上次尝试同步的时间
+	cursor   time.Time            // 上次同步成功的时间，下次从这里往前 revocationSyncOverlap 开始读取
+	purged   time.Time
+}
+
+// NewRevocationStore 创建吊销记录存储
+func NewRevocationStore(db *gorm.DB) *RevocationStore {
+	return &RevocationStore{
+		DB:       db,
+		tokens:   map[string]time.Time{},
+		sessions: map[string]time.Time{},
+	}
+}
+
+// Revoke 吊销单个令牌，expiresAt 为令牌的过期时间
+func (s *RevocationStore) Revoke(ctx context.Context, jti string, userID uint64, expiresAt time.Time) error {
+	row := model.TokenRevocation{JTI: jti, UserID: userID, RevokedAt: time.Now(), ExpiresAt: expiresAt}
+	return s.add(repository.Conn(ctx, s.DB), row)
+}
+
+// add 保存记录，事务提交后加入缓存，回滚的吊销不会影响本实例
+func (s *RevocationStore) add(tx *gorm.DB, rows ...model.TokenRevocation) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return err
+	}
+	repository.AfterCommitDB(tx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, row := range rows {
+			s.apply(row)
+		}
+	})
+	return nil
+}
+
+// IsRevoked 令牌是否已吊销
+func (s *RevocationStore) IsRevoked(ctx context.Context, claims *CustomClaims) bool {
+	s.sync(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if claims.ID != "" {
+		if _, ok := s.tokens[claims.ID]; ok {
+			return true
+		}
+	}
+	if claims.SessionID != "" {
+		if _, ok := s.sessions[claims.SessionID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sync 读取上次同步之后吊销的记录，并清理已过期的记录
+// 查询时不持有锁，同一时间只有一个请求同步；同步失败时继续使用缓存，下个周期重试
+func (s *RevocationStore) sync(ctx context.Context) {
+	now := time.Now()
+	s.mu.Lock()
+	if now.Sub(s.synced) < revocationSyncInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.synced = now
+	cursor := s.cursor
+	purge := now.Sub(s.purged) >= time.Hour
+	if purge {
+		s.purged = now
+	}
+	s.mu.Unlock()
+
+	// 按 id 增量读取会漏掉 id 较小但提交较晚的记录，按吊销时间重叠读取，重复的记录覆盖缓存中相同的值
+	var rows []model.TokenRevocation
+	q := s.DB.WithContext(ctx).Where("expires_at > ?", now)
+	if !cursor.IsZero() {
+		q = q.Where("revoked_at > ?", cursor.Add(-revocationSyncOverlap))
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		slog.ErrorContext(ctx, "同步令牌吊销记录失败", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	for _, row := range rows {
+		s.apply(row)
+	}
+	s.cursor = now
+	for jti, exp := range s.tokens {
+		if !now.Before(exp) {
+			delete(s.tokens, jti)
+		}
+	}
+	for sid, exp := range s.sessions {
+		if !now.Before(exp) {
+			delete(s.sessions, sid)
+		}
+	}
+	s.mu.Unlock()
+
+	// 每小时删除一次数据库中过期的记录
+	if purge {
+		if err := s.DB.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.TokenRevocation{}).Error; err != nil {
+			slog.ErrorContext(ctx, "清理令牌吊销记录失败", "error", err)
+		}
+	}
+}
+
+// apply 将记录加入缓存，调用时持有锁
+func (s *RevocationStore) apply(row model.TokenRevocation) {
+	if row.JTI != "" {
+		s.tokens[row.JTI] = row.ExpiresAt
+		return
+	}
+	if row.SessionID != "" {
+		s.sessions[row.SessionID] = row.ExpiresAt
+	}
+}
+
+// Logout 退出登录，终止令牌所属的会话，没有会话的令牌只吊销该令牌
+func (s *JWTService) Logout(ctx context.Context, claims *CustomClaims) error {
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		if claims.SessionID != "" {
+			return s.revokeSession(repository.Conn(ctx, s.DB), claims.UserID, claims.SessionID)
+		}
+		exp := time.Now().Add(s.accessTTL())
+		if claims.ExpiresAt != nil {
+			exp = claims.ExpiresAt.Time
+		}
+		return s.Revocations.Revoke(ctx, claims.ID, claims.UserID, exp)
+	})
+}
+
+// RevokeUser 吊销用户当前的全部访问令牌和刷新令牌并终止全部会话，用于修改密码、禁用用户
+func (s *JWTService) RevokeUser(ctx context.Context, userID uint64) error {
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		return s.revokeUser(repository.Conn(ctx, s.DB), userID)
+	})
+}
+
+// revokeUser 访问令牌都属于会话，按会话吊销，不依赖签发时间的精度
+func (s *JWTService) revokeUser(tx *gorm.DB, userID uint64) error {
+	now := time.Now()
+	err := tx.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	var sessions []string
+	err = tx.Model(&model.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Pluck("session_id", &sessions).Error
+	if err != nil || len(sessions) == 0 {
+		return err
+	}
+	err = tx.Model(&model.UserSession{}).
+		Where("session_id IN ?", sessions).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	rows := make([]model.TokenRevocation, 0, len(sessions))
+	for _, sid := range sessions {
+		rows = append(rows, model.TokenRevocation{SessionID: sid, UserID: userID, RevokedAt: now, ExpiresAt: now.Add(s.revocationTTL())})
+	}
+	return s.Revocations.add(tx, rows...)
+}
+
+// revokeSession 终止会话，吊销会话的刷新令牌和已签发的访问令牌
+func (s *JWTService) revokeSession(tx *gorm.DB, userID uint64, sessionID string) error {
+	now := time.Now()
+	err := tx.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&model.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	return s.Revocations.add(tx, model.TokenRevocation{SessionID: sessionID, UserID: userID, RevokedAt: now, ExpiresAt: now.Add(s.revocationTTL())})
+}
+
+// revocationTTL 吊销记录的保留时间，吊销前签发的访问令牌在此之后都已过期
+func (s *JWTService) revocationTTL() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maxAccessTTL
+}
+
+// disabledUserCallback 名称，见 revokeDisabledUsers
+const disabledUserCallback = "tier:revoke_disabled_user"
+
+// registerCallbacks 用户状态改为禁用时吊销其令牌，crud 接口和服务中的更新都会触发
+func (s *JWTService) registerCallbacks() {
+	update := s.DB.Callback().Update()
+	if update.Get(disabledUserCallback) != nil {
+		return
+	}
+	_ = update.After("gorm:update").Register(disabledUserCallback, s.revokeDisabledUsers)
+	_ = update.After("gorm:commit_or_rollback_transaction").Register(disabledUserCallback+"_commit", s.applyDisabledUsers)
+}
+
+// revokeDisabledUsers 更新语句将 users.status 设为非 1 时，吊销更新的用户的令牌
+// 用户从模型主键获取，按条件批量更新时需要调用 RevokeUser
+func (s *JWTService) revokeDisabledUsers(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.RowsAffected == 0 || stmt.Schema == nil || stmt.Schema.Table != "users" {
+		return
+	}
+	if status, ok := updatedStatus(stmt); !ok || status == 1 {
+		return
+	}
+
+	var ids []uint64
+	collect := func(v reflect.Value) {
+		if id, ok := v.FieldByName("ID").Interface().(uint64); ok && id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	switch v := reflect.Indirect(stmt.ReflectValue); v.Kind() {
+	case reflect.Struct:
+		collect(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collect(reflect.Indirect(v.Index(i)))
+		}
+	}
+
+	// 不在 repository.Transaction 中时，更新使用 gorm 的默认事务，缓存在其提交后更新，见 applyDisabledUsers
+	ctx := stmt.Context
+	if !repository.InTransaction(ctx) {
+		var run func()
+		ctx, run = repository.DeferCommit(ctx)
+		db.InstanceSet(disabledUserCallback, run)
+	}
+	tx := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	for _, id := range ids {
+		if err := s.revokeUser(tx, id); err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+}
+
+// applyDisabledUsers 更新提交后将 revokeDisabledUsers 的吊销加入缓存
+func (s *JWTService) applyDisabledUsers(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if run, ok := db.InstanceGet(disabledUserCallback); ok {
+		run.(func())()
+	}
+}
+
+// updatedStatus 更新语句写入的 status，无法识别的值（如表达式）视为禁用
+// Updates 结构体时零值不写入，除非通过 Select 指定
+func updatedStatus(stmt *gorm.Statement) (int, bool) {
+	if m, ok := stmt.Dest.(map[string]interface{}); ok {
+		for k, v := range m {
+			if k == "status" || k == "Status" {
+				return toStatus(v), true
+			}
+		}
+		return 0, false
+	}
+
+	field := stmt.Schema.LookUpField("status")
+	v := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+	if field == nil || v.Kind() != reflect.Struct || v.Type() != stmt.Schema.ModelType {
+		return 0, false
+	}
+	value, zero := field.ValueOf(stmt.Context, v)
+	if zero && !slices.Contains(stmt.Selects, "*") && !slices.Contains(stmt.Selects, "status") && !slices.Contains(stmt.Selects, "Status") {
+		return 0, false
+	}
+	return toStatus(value), true
+}
+
+func toStatus(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval 请求更新会话最近使用时间的最小间隔，避免每个请求都写数据库
+const sessionTouchInterval = time.Minute
+
+// Client 登录或刷新令牌的客户端信息
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+// sessionTouches 会话最近一次写入 last_seen_at 的时间
+type sessionTouches struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// due 距上次写入超过间隔时记录本次时间并返回 true
+func (t *sessionTouches) due(sessionID string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.seen == nil {
+		t.seen = map[string]time.Time{}
+	}
+	if last, ok := t.seen[sessionID]; ok && now.Sub(last) < sessionTouchInterval {
+		return false
+	}
+	// 会话数量较多时清理已不活跃的记录
+	if len(t.seen) >= 10000 {
+		for id, last := range t.seen {
+			if now.Sub(last) >= sessionTouchInterval {
+				delete(t.seen, id)
+			}
+		}
+	}
+	t.seen[sessionID] = now
+	return true
+}
+
+// createSession 登录时创建会话
+func createSession(tx *gorm.DB, userID uint64, sessionID string, client Client, expiresAt time.Time) error {
+	now := time.Now()
+	return tx.Create(&model.UserSession{
+		SessionID:  sessionID,
+		UserID:     userID,
+		UserAgent:  truncate(client.UserAgent, 500),
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}).Error
+}
+
+// touchSession 刷新令牌轮换时更新会话的客户端信息和过期时间
+func touchSession(tx *gorm.DB, sessionID string, client Client, expiresAt time.Time) error {
+	return tx.Model(&model.UserSession{}).
+		Where("session_id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"user_agent":   truncate(client.UserAgent, 500),
+			"ip":           client.IP,
+			"last_seen_at": time.Now(),
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// touch 请求通过认证时更新会话的最近使用时间，同一会话每分钟最多写入一次
+func (s *JWTService) touch(ctx context.Context, sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	now := time.Now()
+	if !s.touches.due(sessionID, now) {
+		return
+	}
+	err := s.DB.WithContext(ctx).Model(&model.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("last_seen_at", now).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "更新会话最近使用时间失败", "error", err)
+	}
+}
+
+// Sessions 用户未退出且未过期的会话，按最近使用时间倒序
+func (s *JWTService) Sessions(ctx context.Context, userID uint64) ([]model.UserSession, error) {
+	var sessions []model.UserSession
+	err := repository.Conn(ctx, s.DB).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession 终止用户的会话，会话的访问令牌和刷新令牌立即失效
+// 会话不存在或不属于该用户时返回 gorm.ErrRecordNotFound
+func (s *JWTService) RevokeSession(ctx context.Context, userID, id uint64) error {
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, s.DB)
+		var session model.UserSession
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+			return err
+		}
+		if session.RevokedAt != nil {
+			return nil
+		}
+		return s.revokeSession(tx, userID, session.SessionID)
+	})
+}
+
+// truncate 按字节截断，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,101 @@
+// Package ratelimit 按客户端 IP 限流，使用令牌桶
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"tier-up/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limiter 限流器，配置可以在运行中更新
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New 创建限流器
+func New(c config.RateLimitConfig) *Limiter {
+	l := &Limiter{now: time.Now}
+	l.Update(c)
+	return l
+}
+
+// Update 更新配置，已有的令牌桶清空重新计算
+func (l *Limiter) Update(c config.RateLimitConfig) {
+	burst := float64(c.Burst)
+	if burst == 0 {
+		burst = math.Max(1, math.Ceil(c.Rate))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate, l.burst = c.Rate, burst
+	l.buckets = map[string]*bucket{}
+}
+
+// Allow 消耗 key 的一个令牌，不足时返回需要等待的时间
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep 每分钟删除一次已经补满的令牌桶
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < time.Minute {
+		return
+	}
+	l.swept = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware 超过限制时返回 429 和 Retry-After
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, wait := l.Allow(c.ClientIP())
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 429, "message": "请求过于频繁，请稍后再试"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
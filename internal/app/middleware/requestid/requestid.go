@@ -0,0 +1,42 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header 请求 ID 请求头和响应头
+const Header = "X-Request-ID"
+
+type ctxKey struct{}
+
+// Middleware 读取客户端的 X-Request-ID，没有时生成，写入响应头和请求上下文
+// 服务中通过 FromContext(ctx.Request.Context()) 读取，SQL 日志据此关联请求
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if id == "" || len(id) > 128 {
+			id = New()
+		}
+		c.Set("requestID", id)
+		c.Header(Header, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxKey{}, id))
+		c.Next()
+	}
+}
+
+// New 生成请求 ID
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// FromContext 读取请求 ID，不在请求中时为空
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// IdempotencyKey 幂等请求记录
+type IdempotencyKey struct {
+	Key         string    `gorm:"column:idempotency_key;size:255;primaryKey" json:"key"`
+	Fingerprint string    `gorm:"size:64;not null" json:"fingerprint"` // 请求体摘要
+	Status      string    `gorm:"size:20;not null" json:"status"`      // processing | completed
+	StatusCode  int       `json:"status_code"`                         // 响应状态码
+	ContentType string    `gorm:"size:100" json:"content_type"`        // 响应类型
+	Body        []byte    `json:"-"`                                   // 响应内容
+	ExpiresAt   time.Time `gorm:"index;not null" json:"expires_at"`    // 过期时间
+	CreatedAt   time.Time `json:"created_at"`
+}
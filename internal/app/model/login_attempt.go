@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// LoginAttempt 登录失败计数，Key 为 user:<用户名> 或 ip:<客户端 IP>
+type LoginAttempt struct {
+	ID           uint64     `gorm:"primarykey" json:"id"`
+	Key          string     `gorm:"column:attempt_key;size:255;uniqueIndex;not null" json:"key"`
+	Failures     int        `gorm:"not null;default:0" json:"failures"` // 计数窗口内的失败次数，尝试开始时计入，验证通过后归还
+	LastFailedAt time.Time  `gorm:"index" json:"last_failed_at"`        // 最近一次尝试的时间
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`            // 此前不允许再次尝试
+	LockedAt     *time.Time `json:"locked_at,omitempty"`                // 达到失败次数上限被锁定的时间
+}
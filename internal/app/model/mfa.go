@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// RecoveryCode 两步验证恢复码，只保存 SHA-256 摘要，每个只能使用一次
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primarykey" json:"id"`
+	UserID    uint64     `gorm:"index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFAChallenge 密码验证通过后等待两步验证的登录，只保存令牌的 SHA-256 摘要
+type MFAChallenge struct {
+	ID        uint64     `gorm:"primarykey" json:"id"`
+	UserID    uint64     `gorm:"index;not null" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"` // 验证码错误次数
+	ExpiresAt time.Time  `gorm:"index;not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
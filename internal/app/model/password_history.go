@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// PasswordHistory 用户设置过的密码摘要，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID        uint64    `gorm:"primarykey" json:"id"`
+	UserID    uint64    `gorm:"index;not null" json:"user_id"`
+	Hash      string    `gorm:"size:100;not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// RefreshToken 刷新令牌，只保存摘要
+// 同一次登录轮换出的令牌属于同一个 FamilyID，已使用的令牌再次出现时吊销整个家族
+type RefreshToken struct {
+	ID        uint64     `gorm:"primarykey" json:"id"`
+	UserID    uint64     `gorm:"index;not null" json:"user_id"`
+	FamilyID  string     `gorm:"size:32;index;not null" json:"family_id"` // 登录会话
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`   // SHA-256 摘要
+	ExpiresAt time.Time  `gorm:"index;not null" json:"expires_at"`        // 过期时间
+	UsedAt    *time.Time `json:"used_at"`                                 // 轮换时间，使用后不能再次使用
+	RevokedAt *time.Time `json:"revoked_at"`                              // 吊销时间
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -6,6 +6,7 @@ type Role struct {
 	Name        string `gorm:"size:50;not null;unique" json:"name"`
 	DisplayName string `gorm:"size:100" json:"display_name"`
 	Description string `gorm:"size:200" json:"description"`
+	RequireMFA  bool   `gorm:"column:require_mfa;not null;default:false" json:"require_mfa"` // 拥有该角色的用户必须启用两步验证
 
 	Users []User `gorm:"many2many:user_roles;" json:"-"`
 
@@ -16,4 +17,5 @@ type RoleReq struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 	Description string `json:"description"`
+	RequireMFA  bool   `json:"require_mfa"`
 }
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// TokenRevocation 已吊销的访问令牌
+// JTI 不为空时吊销单个令牌，SessionID 不为空时吊销会话的全部令牌
+// 吊销用户的全部令牌时为用户的每个会话写入一条记录
+// 令牌过期后记录不再需要，ExpiresAt 之后清理
+type TokenRevocation struct {
+	ID        uint64    `gorm:"primarykey" json:"id"`
+	JTI       string    `gorm:"column:jti;size:64;index" json:"jti"`
+	SessionID string    `gorm:"size:32;index" json:"session_id"`
+	UserID    uint64    `gorm:"index;not null" json:"user_id"`
+	RevokedAt time.Time `gorm:"not null" json:"revoked_at"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+}
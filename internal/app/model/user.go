@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // User 用户模型
 type User struct {
 	Base
@@ -12,10 +14,39 @@ type User struct {
 	Avatar   string `gorm:"size:255" json:"avatar"`
 	Status   int    `gorm:"default:1" json:"status"` // 1:正常, 0:禁用
 
+	// PasswordExpiresAt 密码过期时间，到期后登录需要先修改密码，为空时不过期
+	PasswordExpiresAt *time.Time `json:"password_expires_at"`
+
+	// 两步验证，TOTPSecret 在启用前为待确认的密钥，TOTPLastStep 为最近使用的时间步，防止验证码重放
+	TOTPSecret    string     `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"mfa_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+
 	Roles []Role `gorm:"many2many:user_roles;" json:"roles"`
 
 	_ struct{} `crud:"prefix:/user,create,update,delete,page"`
 }
+
+// PasswordExpired 密码是否已过期
+func (u *User) PasswordExpired(now time.Time) bool {
+	return u.PasswordExpiresAt != nil && !now.Before(*u.PasswordExpiresAt)
+}
+
+// MFAEnabled 是否已启用两步验证
+func (u *User) MFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// MFARequired 角色是否要求两步验证，需要预加载 Roles
+func (u *User) MFARequired() bool {
+	for _, r := range u.Roles {
+		if r.RequireMFA {
+			return true
+		}
+	}
+	return false
+}
+
 type UserReq struct {
 	Username string `json:"username"`
 	Nickname string `json:"nickname"`
@@ -26,7 +57,10 @@ type UserReq struct {
 }
 
 // UserRole 用户角色关联表
+// 只开放查询，分配角色需通过 /user/:id/role 以同步Casbin规则
 type UserRole struct {
-	UserID uint `gorm:"primaryKey"`
-	RoleID uint `gorm:"primaryKey"`
+	UserID uint `gorm:"primaryKey" json:"user_id"`
+	RoleID uint `gorm:"primaryKey" json:"role_id"`
+
+	_ struct{} `crud:"prefix:/user-role,page"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// UserSession 登录会话，每次登录创建，刷新令牌轮换时延长
+// SessionID 即刷新令牌的 FamilyID，也写入访问令牌的 sid
+type UserSession struct {
+	ID         uint64     `gorm:"primarykey" json:"id"`
+	SessionID  string     `gorm:"size:32;uniqueIndex;not null" json:"-"`
+	UserID     uint64     `gorm:"index;not null" json:"user_id"`
+	UserAgent  string     `gorm:"size:500" json:"user_agent"`
+	IP         string     `gorm:"column:ip;size:64" json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`                     // 最近一次请求或刷新令牌的时间
+	ExpiresAt  time.Time  `gorm:"index;not null" json:"expires_at"` // 刷新令牌过期时间
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`             // 退出登录或被终止的时间
+}
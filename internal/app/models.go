@@ -0,0 +1,26 @@
+package app
+
+import (
+	"tier-up/internal/app/model"
+	"tier-up/internal/crud"
+)
+
+// 模型注册
+// 新增模型只需在此注册，数据库迁移、crud 路由和文档生成都从注册表读取
+// 注册顺序即迁移顺序
+func init() {
+	// 系统表
+	crud.Register[model.User, model.UserReq](crud.Options{Access: crud.AccessAuth})
+	crud.Register[model.Role, model.RoleReq](crud.Options{})
+	crud.Register[model.UserRole, model.UserRole](crud.Options{})
+	crud.Register[model.Menu, model.MenuReq](crud.Options{})
+	// 内部表，只迁移和生成查询代码，不生成 crud 路由
+	crud.RegisterTable[model.IdempotencyKey]()
+	crud.RegisterTable[model.RefreshToken]()
+	crud.RegisterTable[model.TokenRevocation]()
+	crud.RegisterTable[model.UserSession]()
+	crud.RegisterTable[model.LoginAttempt]()
+	crud.RegisterTable[model.PasswordHistory]()
+	crud.RegisterTable[model.RecoveryCode]()
+	crud.RegisterTable[model.MFAChallenge]()
+}
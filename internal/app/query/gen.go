@@ -0,0 +1,159 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+
+	"gorm.io/gen"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
+	return &Query{
+		db:              db,
+		IdempotencyKey:  newIdempotencyKey(db, opts...),
+		LoginAttempt:    newLoginAttempt(db, opts...),
+		MFAChallenge:    newMFAChallenge(db, opts...),
+		Menu:            newMenu(db, opts...),
+		PasswordHistory: newPasswordHistory(db, opts...),
+		RecoveryCode:    newRecoveryCode(db, opts...),
+		RefreshToken:    newRefreshToken(db, opts...),
+		Role:            newRole(db, opts...),
+		TokenRevocation: newTokenRevocation(db, opts...),
+		User:            newUser(db, opts...),
+		UserRole:        newUserRole(db, opts...),
+		UserSession:     newUserSession(db, opts...),
+	}
+}
+
+type Query struct {
+	db *gorm.DB
+
+	IdempotencyKey  idempotencyKey
+	LoginAttempt    loginAttempt
+	MFAChallenge    mFAChallenge
+	Menu            menu
+	PasswordHistory passwordHistory
+	RecoveryCode    recoveryCode
+	RefreshToken    refreshToken
+	Role            role
+	TokenRevocation tokenRevocation
+	User            user
+	UserRole        userRole
+	UserSession     userSession
+}
+
+func (q *Query) Available() bool { return q.db != nil }
+
+func (q *Query) clone(db *gorm.DB) *Query {
+	return &Query{
+		db:              db,
+		IdempotencyKey:  q.IdempotencyKey.clone(db),
+		LoginAttempt:    q.LoginAttempt.clone(db),
+		MFAChallenge:    q.MFAChallenge.clone(db),
+		Menu:            q.Menu.clone(db),
+		PasswordHistory: q.PasswordHistory.clone(db),
+		RecoveryCode:    q.RecoveryCode.clone(db),
+		RefreshToken:    q.RefreshToken.clone(db),
+		Role:            q.Role.clone(db),
+		TokenRevocation: q.TokenRevocation.clone(db),
+		User:            q.User.clone(db),
+		UserRole:        q.UserRole.clone(db),
+		UserSession:     q.UserSession.clone(db),
+	}
+}
+
+func (q *Query) ReadDB() *Query {
+	return q.ReplaceDB(q.db.Clauses(dbresolver.Read))
+}
+
+func (q *Query) WriteDB() *Query {
+	return q.ReplaceDB(q.db.Clauses(dbresolver.Write))
+}
+
+func (q *Query) ReplaceDB(db *gorm.DB) *Query {
+	return &Query{
+		db:              db,
+		IdempotencyKey:  q.IdempotencyKey.replaceDB(db),
+		LoginAttempt:    q.LoginAttempt.replaceDB(db),
+		MFAChallenge:    q.MFAChallenge.replaceDB(db),
+		Menu:            q.Menu.replaceDB(db),
+		PasswordHistory: q.PasswordHistory.replaceDB(db),
+		RecoveryCode:    q.RecoveryCode.replaceDB(db),
+		RefreshToken:    q.RefreshToken.replaceDB(db),
+		Role:            q.Role.replaceDB(db),
+		TokenRevocation: q.TokenRevocation.replaceDB(db),
+		User:            q.User.replaceDB(db),
+		UserRole:        q.UserRole.replaceDB(db),
+		UserSession:     q.UserSession.replaceDB(db),
+	}
+}
+
+type queryCtx struct {
+	IdempotencyKey  IIdempotencyKeyDo
+	LoginAttempt    ILoginAttemptDo
+	MFAChallenge    IMFAChallengeDo
+	Menu            IMenuDo
+	PasswordHistory IPasswordHistoryDo
+	RecoveryCode    IRecoveryCodeDo
+	RefreshToken    IRefreshTokenDo
+	Role            IRoleDo
+	TokenRevocation ITokenRevocationDo
+	User            IUserDo
+	UserRole        IUserRoleDo
+	UserSession     IUserSessionDo
+}
+
+func (q *Query) WithContext(ctx context.Context) *queryCtx {
+	return &queryCtx{
+		IdempotencyKey:  q.IdempotencyKey.WithContext(ctx),
+		LoginAttempt:    q.LoginAttempt.WithContext(ctx),
+		MFAChallenge:    q.MFAChallenge.WithContext(ctx),
+		Menu:            q.Menu.WithContext(ctx),
+		PasswordHistory: q.PasswordHistory.WithContext(ctx),
+		RecoveryCode:    q.RecoveryCode.WithContext(ctx),
+		RefreshToken:    q.RefreshToken.WithContext(ctx),
+		Role:            q.Role.WithContext(ctx),
+		TokenRevocation: q.TokenRevocation.WithContext(ctx),
+		User:            q.User.WithContext(ctx),
+		UserRole:        q.UserRole.WithContext(ctx),
+		UserSession:     q.UserSession.WithContext(ctx),
+	}
+}
+
+func (q *Query) Transaction(fc func(tx *Query) error, opts ...*sql.TxOptions) error {
+	return q.db.Transaction(func(tx *gorm.DB) error { return fc(q.clone(tx)) }, opts...)
+}
+
+func (q *Query) Begin(opts ...*sql.TxOptions) *QueryTx {
+	tx := q.db.Begin(opts...)
+	return &QueryTx{Query: q.clone(tx), Error: tx.Error}
+}
+
+type QueryTx struct {
+	*Query
+	Error error
+}
+
+func (q *QueryTx) Commit() error {
+	return q.db.Commit().Error
+}
+
+func (q *QueryTx) Rollback() error {
+	return q.db.Rollback().Error
+}
+
+func (q *QueryTx) SavePoint(name string) error {
+	return q.db.SavePoint(name).Error
+}
+
+func (q *QueryTx) RollbackTo(name string) error {
+	return q.db.RollbackTo(name).Error
+}
@@ -0,0 +1,414 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"tier-up/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newLoginAttempt(db *gorm.DB, opts ...gen.DOOption) loginAttempt {
+	_loginAttempt := loginAttempt{}
+
+	_loginAttempt.loginAttemptDo.UseDB(db, opts...)
+	_loginAttempt.loginAttemptDo.UseModel(&model.LoginAttempt{})
+
+	tableName := _loginAttempt.loginAttemptDo.TableName()
+	_loginAttempt.ALL = field.NewAsterisk(tableName)
+	_loginAttempt.ID = field.NewUint64(tableName, "id")
+	_loginAttempt.Key = field.NewString(tableName, "attempt_key")
+	_loginAttempt.Failures = field.NewInt(tableName, "failures")
+	_loginAttempt.LastFailedAt = field.NewTime(tableName, "last_failed_at")
+	_loginAttempt.BlockedUntil = field.NewTime(tableName, "blocked_until")
+	_loginAttempt.LockedAt = field.NewTime(tableName, "locked_at")
+
+	_loginAttempt.fillFieldMap()
+
+	return _loginAttempt
+}
+
+type loginAttempt struct {
+	loginAttemptDo loginAttemptDo
+
+	ALL          field.Asterisk
+	ID           field.Uint64
+	Key          field.String
+	Failures     field.Int
+	LastFailedAt field.Time
+	BlockedUntil field.Time
+	LockedAt     field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (l loginAttempt) Table(newTableName string) *loginAttempt {
+	l.loginAttemptDo.UseTable(newTableName)
+	return l.updateTableName(newTableName)
+}
+
+func (l loginAttempt) As(alias string) *loginAttempt {
+	l.loginAttemptDo.DO = *(l.loginAttemptDo.As(alias).(*gen.DO))
+	return l.updateTableName(alias)
+}
+
+func (l *loginAttempt) updateTableName(table string) *loginAttempt {
+	l.ALL = field.NewAsterisk(table)
+	l.ID = field.NewUint64(table, "id")
+	l.Key = field.NewString(table, "attempt_key")
+	l.Failures = field.NewInt(table, "failures")
+	l.LastFailedAt = field.NewTime(table, "last_failed_at")
+	l.BlockedUntil = field.NewTime(table, "blocked_until")
+	l.LockedAt = field.NewTime(table, "locked_at")
+
+	l.fillFieldMap()
+
+	return l
+}
+
+func (l *loginAttempt) WithContext(ctx context.Context) ILoginAttemptDo {
+	return l.loginAttemptDo.WithContext(ctx)
+}
+
+func (l loginAttempt) TableName() string { return l.loginAttemptDo.TableName() }
+
+func (l loginAttempt) Alias() string { return l.loginAttemptDo.Alias() }
+
+func (l loginAttempt) Columns(cols ...field.Expr) gen.Columns {
+	return l.loginAttemptDo.Columns(cols...)
+}
+
+func (l *loginAttempt) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := l.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (l *loginAttempt) fillFieldMap() {
+	l.fieldMap = make(map[string]field.Expr, 6)
+	l.fieldMap["id"] = l.ID
+	l.fieldMap["attempt_key"] = l.Key
+	l.fieldMap["failures"] = l.Failures
+	l.fieldMap["last_failed_at"] = l.LastFailedAt
+	l.fieldMap["blocked_until"] = l.BlockedUntil
+	l.fieldMap["locked_at"] = l.LockedAt
+}
+
+func (l loginAttempt) clone(db *gorm.DB) loginAttempt {
+	l.loginAttemptDo.ReplaceConnPool(db.Statement.ConnPool)
+	return l
+}
+
+func (l loginAttempt) replaceDB(db *gorm.DB) loginAttempt {
+	l.loginAttemptDo.ReplaceDB(db)
+	return l
+}
+
+type loginAttemptDo struct{ gen.DO }
+
+type ILoginAttemptDo interface {
+	gen.SubQuery
+	Debug() ILoginAttemptDo
+	WithContext(ctx context.Context) ILoginAttemptDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ILoginAttemptDo
+	WriteDB() ILoginAttemptDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ILoginAttemptDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ILoginAttemptDo
+	Not(conds ...gen.Condition) ILoginAttemptDo
+	Or(conds ...gen.Condition) ILoginAttemptDo
+	Select(conds ...field.Expr) ILoginAttemptDo
+	Where(conds ...gen.Condition) ILoginAttemptDo
+	Order(conds ...field.Expr) ILoginAttemptDo
+	Distinct(cols ...field.Expr) ILoginAttemptDo
+	Omit(cols ...field.Expr) ILoginAttemptDo
+	Join(table schema.Tabler, on ...field.Expr) ILoginAttemptDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ILoginAttemptDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ILoginAttemptDo
+	Group(cols ...field.Expr) ILoginAttemptDo
+	Having(conds ...gen.Condition) ILoginAttemptDo
+	Limit(limit int) ILoginAttemptDo
+	Offset(offset int) ILoginAttemptDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ILoginAttemptDo
+	Unscoped() ILoginAttemptDo
+	Create(values ...*model.LoginAttempt) error
+	CreateInBatches(values []*model.LoginAttempt, batchSize int) error
+	Save(values ...*model.LoginAttempt) error
+	First() (*model.LoginAttempt, error)
+	Take() (*model.LoginAttempt, error)
+	Last() (*model.LoginAttempt, error)
+	Find() ([]*model.LoginAttempt, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.LoginAttempt, err error)
+	FindInBatches(result *[]*model.LoginAttempt, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.LoginAttempt) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ILoginAttemptDo
+	Assign(attrs ...field.AssignExpr) ILoginAttemptDo
+	Joins(fields ...field.RelationField) ILoginAttemptDo
+	Preload(fields ...field.RelationField) ILoginAttemptDo
+	FirstOrInit() (*model.LoginAttempt, error)
+	FirstOrCreate() (*model.LoginAttempt, error)
+	FindByPage(offset int, limit int) (result []*model.LoginAttempt, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ILoginAttemptDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (l loginAttemptDo) Debug() ILoginAttemptDo {
+	return l.withDO(l.DO.Debug())
+}
+
+func (l loginAttemptDo) WithContext(ctx context.Context) ILoginAttemptDo {
+	return l.withDO(l.DO.WithContext(ctx))
+}
+
+func (l loginAttemptDo) ReadDB() ILoginAttemptDo {
+	return l.Clauses(dbresolver.Read)
+}
+
+func (l loginAttemptDo) WriteDB() ILoginAttemptDo {
+	return l.Clauses(dbresolver.Write)
+}
+
+func (l loginAttemptDo) Session(config *gorm.Session) ILoginAttemptDo {
+	return l.withDO(l.DO.Session(config))
+}
+
+func (l loginAttemptDo) Clauses(conds ...clause.Expression) ILoginAttemptDo {
+	return l.withDO(l.DO.Clauses(conds...))
+}
+
+func (l loginAttemptDo) Returning(value interface{}, columns ...string) ILoginAttemptDo {
+	return l.withDO(l.DO.Returning(value, columns...))
+}
+
+func (l loginAttemptDo) Not(conds ...gen.Condition) ILoginAttemptDo {
+	return l.withDO(l.DO.Not(conds...))
+}
+
+func (l loginAttemptDo) Or(conds ...gen.Condition) ILoginAttemptDo {
+	return l.withDO(l.DO.Or(conds...))
+}
+
+func (l loginAttemptDo) Select(conds ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Select(conds...))
+}
+
+func (l loginAttemptDo) Where(conds ...gen.Condition) ILoginAttemptDo {
+	return l.withDO(l.DO.Where(conds...))
+}
+
+func (l loginAttemptDo) Order(conds ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Order(conds...))
+}
+
+func (l loginAttemptDo) Distinct(cols ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Distinct(cols...))
+}
+
+func (l loginAttemptDo) Omit(cols ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Omit(cols...))
+}
+
+func (l loginAttemptDo) Join(table schema.Tabler, on ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Join(table, on...))
+}
+
+func (l loginAttemptDo) LeftJoin(table schema.Tabler, on ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.LeftJoin(table, on...))
+}
+
+func (l loginAttemptDo) RightJoin(table schema.Tabler, on ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.RightJoin(table, on...))
+}
+
+func (l loginAttemptDo) Group(cols ...field.Expr) ILoginAttemptDo {
+	return l.withDO(l.DO.Group(cols...))
+}
+
+func (l loginAttemptDo) Having(conds ...gen.Condition) ILoginAttemptDo {
+	return l.withDO(l.DO.Having(conds...))
+}
+
+func (l loginAttemptDo) Limit(limit int) ILoginAttemptDo {
+	return l.withDO(l.DO.Limit(limit))
+}
+
+func (l loginAttemptDo) Offset(offset int) ILoginAttemptDo {
+	return l.withDO(l.DO.Offset(offset))
+}
+
+func (l loginAttemptDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ILoginAttemptDo {
+	return l.withDO(l.DO.Scopes(funcs...))
+}
+
+func (l loginAttemptDo) Unscoped() ILoginAttemptDo {
+	return l.withDO(l.DO.Unscoped())
+}
+
+func (l loginAttemptDo) Create(values ...*model.LoginAttempt) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return l.DO.Create(values)
+}
+
+func (l loginAttemptDo) CreateInBatches(values []*model.LoginAttempt, batchSize int) error {
+	return l.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (l loginAttemptDo) Save(values ...*model.LoginAttempt) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return l.DO.Save(values)
+}
+
+func (l loginAttemptDo) First() (*model.LoginAttempt, error) {
+	if result, err := l.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LoginAttempt), nil
+	}
+}
+
+func (l loginAttemptDo) Take() (*model.LoginAttempt, error) {
+	if result, err := l.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LoginAttempt), nil
+	}
+}
+
+func (l loginAttemptDo) Last() (*model.LoginAttempt, error) {
+	if result, err := l.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LoginAttempt), nil
+	}
+}
+
+func (l loginAttemptDo) Find() ([]*model.LoginAttempt, error) {
+	result, err := l.DO.Find()
+	return result.([]*model.LoginAttempt), err
+}
+
+func (l loginAttemptDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.LoginAttempt, err error) {
+	buf := make([]*model.LoginAttempt, 0, batchSize)
+	err = l.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (l loginAttemptDo) FindInBatches(result *[]*model.LoginAttempt, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return l.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (l loginAttemptDo) Attrs(attrs ...field.AssignExpr) ILoginAttemptDo {
+	return l.withDO(l.DO.Attrs(attrs...))
+}
+
+func (l loginAttemptDo) Assign(attrs ...field.AssignExpr) ILoginAttemptDo {
+	return l.withDO(l.DO.Assign(attrs...))
+}
+
+func (l loginAttemptDo) Joins(fields ...field.RelationField) ILoginAttemptDo {
+	for _, _f := range fields {
+		l = *l.withDO(l.DO.Joins(_f))
+	}
+	return &l
+}
+
+func (l loginAttemptDo) Preload(fields ...field.RelationField) ILoginAttemptDo {
+	for _, _f := range fields {
+		l = *l.withDO(l.DO.Preload(_f))
+	}
+	return &l
+}
+
+func (l loginAttemptDo) FirstOrInit() (*model.LoginAttempt, error) {
+	if result, err := l.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LoginAttempt), nil
+	}
+}
+
+func (l loginAttemptDo) FirstOrCreate() (*model.LoginAttempt, error) {
+	if result, err := l.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LoginAttempt), nil
+	}
+}
+
+func (l loginAttemptDo) FindByPage(offset int, limit int) (result []*model.LoginAttempt, count int64, err error) {
+	result, err = l.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = l.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (l loginAttemptDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = l.Count()
+	if err != nil {
+		return
+	}
+
+	err = l.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (l loginAttemptDo) Scan(result interface{}) (err error) {
+	return l.DO.Scan(result)
+}
+
+func (l loginAttemptDo) Delete(models ...*model.LoginAttempt) (result gen.ResultInfo, err error) {
+	return l.DO.Delete(models)
+}
+
+func (l *loginAttemptDo) withDO(do gen.Dao) *loginAttemptDo {
+	l.DO = *do.(*gen.DO)
+	return l
+}
@@ -0,0 +1,418 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"tier-up/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newMFAChallenge(db *gorm.DB, opts ...gen.DOOption) mFAChallenge {
+	_mFAChallenge := mFAChallenge{}
+
+	_mFAChallenge.mFAChallengeDo.UseDB(db, opts...)
+	_mFAChallenge.mFAChallengeDo.UseModel(&model.MFAChallenge{})
+
+	tableName := _mFAChallenge.mFAChallengeDo.TableName()
+	_mFAChallenge.ALL = field.NewAsterisk(tableName)
+	_mFAChallenge.ID = field.NewUint64(tableName, "id")
+	_mFAChallenge.UserID = field.NewUint64(tableName, "user_id")
+	_mFAChallenge.TokenHash = field.NewString(tableName, "token_hash")
+	_mFAChallenge.Attempts = field.NewInt(tableName, "attempts")
+	_mFAChallenge.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_mFAChallenge.UsedAt = field.NewTime(tableName, "used_at")
+	_mFAChallenge.CreatedAt = field.NewTime(tableName, "created_at")
+
+	_mFAChallenge.fillFieldMap()
+
+	return _mFAChallenge
+}
+
+type mFAChallenge struct {
+	mFAChallengeDo mFAChallengeDo
+
+	ALL       field.Asterisk
+	ID        field.Uint64
+	UserID    field.Uint64
+	TokenHash field.String
+	Attempts  field.Int
+	ExpiresAt field.Time
+	UsedAt    field.Time
+	CreatedAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (m mFAChallenge) Table(newTableName string) *mFAChallenge {
+	m.mFAChallengeDo.UseTable(newTableName)
+	return m.updateTableName(newTableName)
+}
+
+func (m mFAChallenge) As(alias string) *mFAChallenge {
+	m.mFAChallengeDo.DO = *(m.mFAChallengeDo.As(alias).(*gen.DO))
+	return m.updateTableName(alias)
+}
+
+func (m *mFAChallenge) updateTableName(table string) *mFAChallenge {
+	m.ALL = field.NewAsterisk(table)
+	m.ID = field.NewUint64(table, "id")
+	m.UserID = field.NewUint64(table, "user_id")
+	m.TokenHash = field.NewString(table, "token_hash")
+	m.Attempts = field.NewInt(table, "attempts")
+	m.ExpiresAt = field.NewTime(table, "expires_at")
+	m.UsedAt = field.NewTime(table, "used_at")
+	m.CreatedAt = field.NewTime(table, "created_at")
+
+	m.fillFieldMap()
+
+	return m
+}
+
+func (m *mFAChallenge) WithContext(ctx context.Context) IMFAChallengeDo {
+	return m.mFAChallengeDo.WithContext(ctx)
+}
+
+func (m mFAChallenge) TableName() string { return m.mFAChallengeDo.TableName() }
+
+func (m mFAChallenge) Alias() string { return m.mFAChallengeDo.Alias() }
+
+func (m mFAChallenge) Columns(cols ...field.Expr) gen.Columns {
+	return m.mFAChallengeDo.Columns(cols...)
+}
+
+func (m *mFAChallenge) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := m.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (m *mFAChallenge) fillFieldMap() {
+	m.fieldMap = make(map[string]field.Expr, 7)
+	m.fieldMap["id"] = m.ID
+	m.fieldMap["user_id"] = m.UserID
+	m.fieldMap["token_hash"] = m.TokenHash
+	m.fieldMap["attempts"] = m.Attempts
+	m.fieldMap["expires_at"] = m.ExpiresAt
+	m.fieldMap["used_at"] = m.UsedAt
+	m.fieldMap["created_at"] = m.CreatedAt
+}
+
+func (m mFAChallenge) clone(db *gorm.DB) mFAChallenge {
+	m.mFAChallengeDo.ReplaceConnPool(db.Statement.ConnPool)
+	return m
+}
+
+func (m mFAChallenge) replaceDB(db *gorm.DB) mFAChallenge {
+	m.mFAChallengeDo.ReplaceDB(db)
+	return m
+}
+
+type mFAChallengeDo struct{ gen.DO }
+
+type IMFAChallengeDo interface {
+	gen.SubQuery
+	Debug() IMFAChallengeDo
+	WithContext(ctx context.Context) IMFAChallengeDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IMFAChallengeDo
+	WriteDB() IMFAChallengeDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IMFAChallengeDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IMFAChallengeDo
+	Not(conds ...gen.Condition) IMFAChallengeDo
+	Or(conds ...gen.Condition) IMFAChallengeDo
+	Select(conds ...field.Expr) IMFAChallengeDo
+	Where(conds ...gen.Condition) IMFAChallengeDo
+	Order(conds ...field.Expr) IMFAChallengeDo
+	Distinct(cols ...field.Expr) IMFAChallengeDo
+	Omit(cols ...field.Expr) IMFAChallengeDo
+	Join(table schema.Tabler, on ...field.Expr) IMFAChallengeDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IMFAChallengeDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IMFAChallengeDo
+	Group(cols ...field.Expr) IMFAChallengeDo
+	Having(conds ...gen.Condition) IMFAChallengeDo
+	Limit(limit int) IMFAChallengeDo
+	Offset(offset int) IMFAChallengeDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IMFAChallengeDo
+	Unscoped() IMFAChallengeDo
+	Create(values ...*model.MFAChallenge) error
+	CreateInBatches(values []*model.MFAChallenge, batchSize int) error
+	Save(values ...*model.MFAChallenge) error
+	First() (*model.MFAChallenge, error)
+	Take() (*model.MFAChallenge, error)
+	Last() (*model.MFAChallenge, error)
+	Find() ([]*model.MFAChallenge, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.MFAChallenge, err error)
+	FindInBatches(result *[]*model.MFAChallenge, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.MFAChallenge) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IMFAChallengeDo
+	Assign(attrs ...field.AssignExpr) IMFAChallengeDo
+	Joins(fields ...field.RelationField) IMFAChallengeDo
+	Preload(fields ...field.RelationField) IMFAChallengeDo
+	FirstOrInit() (*model.MFAChallenge, error)
+	FirstOrCreate() (*model.MFAChallenge, error)
+	FindByPage(offset int, limit int) (result []*model.MFAChallenge, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IMFAChallengeDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (m mFAChallengeDo) Debug() IMFAChallengeDo {
+	return m.withDO(m.DO.Debug())
+}
+
+func (m mFAChallengeDo) WithContext(ctx context.Context) IMFAChallengeDo {
+	return m.withDO(m.DO.WithContext(ctx))
+}
+
+func (m mFAChallengeDo) ReadDB() IMFAChallengeDo {
+	return m.Clauses(dbresolver.Read)
+}
+
+func (m mFAChallengeDo) WriteDB() IMFAChallengeDo {
+	return m.Clauses(dbresolver.Write)
+}
+
+func (m mFAChallengeDo) Session(config *gorm.Session) IMFAChallengeDo {
+	return m.withDO(m.DO.Session(config))
+}
+
+func (m mFAChallengeDo) Clauses(conds ...clause.Expression) IMFAChallengeDo {
+	return m.withDO(m.DO.Clauses(conds...))
+}
+
+func (m mFAChallengeDo) Returning(value interface{}, columns ...string) IMFAChallengeDo {
+	return m.withDO(m.DO.Returning(value, columns...))
+}
+
+func (m mFAChallengeDo) Not(conds ...gen.Condition) IMFAChallengeDo {
+	return m.withDO(m.DO.Not(conds...))
+}
+
+func (m mFAChallengeDo) Or(conds ...gen.Condition) IMFAChallengeDo {
+	return m.withDO(m.DO.Or(conds...))
+}
+
+func (m mFAChallengeDo) Select(conds ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Select(conds...))
+}
+
+func (m mFAChallengeDo) Where(conds ...gen.Condition) IMFAChallengeDo {
+	return m.withDO(m.DO.Where(conds...))
+}
+
+func (m mFAChallengeDo) Order(conds ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Order(conds...))
+}
+
+func (m mFAChallengeDo) Distinct(cols ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Distinct(cols...))
+}
+
+func (m mFAChallengeDo) Omit(cols ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Omit(cols...))
+}
+
+func (m mFAChallengeDo) Join(table schema.Tabler, on ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Join(table, on...))
+}
+
+func (m mFAChallengeDo) LeftJoin(table schema.Tabler, on ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.LeftJoin(table, on...))
+}
+
+func (m mFAChallengeDo) RightJoin(table schema.Tabler, on ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.RightJoin(table, on...))
+}
+
+func (m mFAChallengeDo) Group(cols ...field.Expr) IMFAChallengeDo {
+	return m.withDO(m.DO.Group(cols...))
+}
+
+func (m mFAChallengeDo) Having(conds ...gen.Condition) IMFAChallengeDo {
+	return m.withDO(m.DO.Having(conds...))
+}
+
+func (m mFAChallengeDo) Limit(limit int) IMFAChallengeDo {
+	return m.withDO(m.DO.Limit(limit))
+}
+
+func (m mFAChallengeDo) Offset(offset int) IMFAChallengeDo {
+	return m.withDO(m.DO.Offset(offset))
+}
+
+func (m mFAChallengeDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IMFAChallengeDo {
+	return m.withDO(m.DO.Scopes(funcs...))
+}
+
+func (m mFAChallengeDo) Unscoped() IMFAChallengeDo {
+	return m.withDO(m.DO.Unscoped())
+}
+
+func (m mFAChallengeDo) Create(values ...*model.MFAChallenge) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return m.DO.Create(values)
+}
+
+func (m mFAChallengeDo) CreateInBatches(values []*model.MFAChallenge, batchSize int) error {
+	return m.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (m mFAChallengeDo) Save(values ...*model.MFAChallenge) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return m.DO.Save(values)
+}
+
+func (m mFAChallengeDo) First() (*model.MFAChallenge, error) {
+	if result, err := m.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.MFAChallenge), nil
+	}
+}
+
+func (m mFAChallengeDo) Take() (*model.MFAChallenge, error) {
+	if result, err := m.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.MFAChallenge), nil
+	}
+}
+
+func (m mFAChallengeDo) Last() (*model.MFAChallenge, error) {
+	if result, err := m.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.MFAChallenge), nil
+	}
+}
+
+func (m mFAChallengeDo) Find() ([]*model.MFAChallenge, error) {
+	result, err := m.DO.Find()
+	return result.([]*model.MFAChallenge), err
+}
+
+func (m mFAChallengeDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.MFAChallenge, err error) {
+	buf := make([]*model.MFAChallenge, 0, batchSize)
+	err = m.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (m mFAChallengeDo) FindInBatches(result *[]*model.MFAChallenge, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return m.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (m mFAChallengeDo) Attrs(attrs ...field.AssignExpr) IMFAChallengeDo {
+	return m.withDO(m.DO.Attrs(attrs...))
+}
+
+func (m mFAChallengeDo) Assign(attrs ...field.AssignExpr) IMFAChallengeDo {
+	return m.withDO(m.DO.Assign(attrs...))
+}
+
+func (m mFAChallengeDo) Joins(fields ...field.RelationField) IMFAChallengeDo {
+	for _, _f := range fields {
+		m = *m.withDO(m.DO.Joins(_f))
+	}
+	return &m
+}
+
+func (m mFAChallengeDo) Preload(fields ...field.RelationField) IMFAChallengeDo {
+	for _, _f := range fields {
+		m = *m.withDO(m.DO.Preload(_f))
+	}
+	return &m
+}
+
+func (m mFAChallengeDo) FirstOrInit() (*model.MFAChallenge, error) {
+	if result, err := m.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.MFAChallenge), nil
+	}
+}
+
+func (m mFAChallengeDo) FirstOrCreate() (*model.MFAChallenge, error) {
+	if result, err := m.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.MFAChallenge), nil
+	}
+}
+
+func (m mFAChallengeDo) FindByPage(offset int, limit int) (result []*model.MFAChallenge, count int64, err error) {
+	result, err = m.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = m.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (m mFAChallengeDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = m.Count()
+	if err != nil {
+		return
+	}
+
+	err = m.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (m mFAChallengeDo) Scan(result interface{}) (err error) {
+	return m.DO.Scan(result)
+}
+
+func (m mFAChallengeDo) Delete(models ...*model.MFAChallenge) (result gen.ResultInfo, err error) {
+	return m.DO.Delete(models)
+}
+
+func (m *mFAChallengeDo) withDO(do gen.Dao) *mFAChallengeDo {
+	m.DO = *do.(*gen.DO)
+	return m
+}
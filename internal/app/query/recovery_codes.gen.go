@@ -0,0 +1,410 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"tier-up/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newRecoveryCode(db *gorm.DB, opts ...gen.DOOption) recoveryCode {
+	_recoveryCode := recoveryCode{}
+
+	_recoveryCode.recoveryCodeDo.UseDB(db, opts...)
+	_recoveryCode.recoveryCodeDo.UseModel(&model.RecoveryCode{})
+
+	tableName := _recoveryCode.recoveryCodeDo.TableName()
+	_recoveryCode.ALL = field.NewAsterisk(tableName)
+	_recoveryCode.ID = field.NewUint64(tableName, "id")
+	_recoveryCode.UserID = field.NewUint64(tableName, "user_id")
+	_recoveryCode.CodeHash = field.NewString(tableName, "code_hash")
+	_recoveryCode.UsedAt = field.NewTime(tableName, "used_at")
+	_recoveryCode.CreatedAt = field.NewTime(tableName, "created_at")
+
+	_recoveryCode.fillFieldMap()
+
+	return _recoveryCode
+}
+
+type recoveryCode struct {
+	recoveryCodeDo recoveryCodeDo
+
+	ALL       field.Asterisk
+	ID        field.Uint64
+	UserID    field.Uint64
+	CodeHash  field.String
+	UsedAt    field.Time
+	CreatedAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (r recoveryCode) Table(newTableName string) *recoveryCode {
+	r.recoveryCodeDo.UseTable(newTableName)
+	return r.updateTableName(newTableName)
+}
+
+func (r recoveryCode) As(alias string) *recoveryCode {
+	r.recoveryCodeDo.DO = *(r.recoveryCodeDo.As(alias).(*gen.DO))
+	return r.updateTableName(alias)
+}
+
+func (r *recoveryCode) updateTableName(table string) *recoveryCode {
+	r.ALL = field.NewAsterisk(table)
+	r.ID = field.NewUint64(table, "id")
+	r.UserID = field.NewUint64(table, "user_id")
+	r.CodeHash = field.NewString(table, "code_hash")
+	r.UsedAt = field.NewTime(table, "used_at")
+	r.CreatedAt = field.NewTime(table, "created_at")
+
+	r.fillFieldMap()
+
+	return r
+}
+
+func (r *recoveryCode) WithContext(ctx context.Context) IRecoveryCodeDo {
+	return r.recoveryCodeDo.WithContext(ctx)
+}
+
+func (r recoveryCode) TableName() string { return r.recoveryCodeDo.TableName() }
+
+func (r recoveryCode) Alias() string { return r.recoveryCodeDo.Alias() }
+
+func (r recoveryCode) Columns(cols ...field.Expr) gen.Columns {
+	return r.recoveryCodeDo.Columns(cols...)
+}
+
+func (r *recoveryCode) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := r.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (r *recoveryCode) fillFieldMap() {
+	r.fieldMap = make(map[string]field.Expr, 5)
+	r.fieldMap["id"] = r.ID
+	r.fieldMap["user_id"] = r.UserID
+	r.fieldMap["code_hash"] = r.CodeHash
+	r.fieldMap["used_at"] = r.UsedAt
+	r.fieldMap["created_at"] = r.CreatedAt
+}
+
+func (r recoveryCode) clone(db *gorm.DB) recoveryCode {
+	r.recoveryCodeDo.ReplaceConnPool(db.Statement.ConnPool)
+	return r
+}
+
+func (r recoveryCode) replaceDB(db *gorm.DB) recoveryCode {
+	r.recoveryCodeDo.ReplaceDB(db)
+	return r
+}
+
+type recoveryCodeDo struct{ gen.DO }
+
+type IRecoveryCodeDo interface {
+	gen.SubQuery
+	Debug() IRecoveryCodeDo
+	WithContext(ctx context.Context) IRecoveryCodeDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IRecoveryCodeDo
+	WriteDB() IRecoveryCodeDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IRecoveryCodeDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IRecoveryCodeDo
+	Not(conds ...gen.Condition) IRecoveryCodeDo
+	Or(conds ...gen.Condition) IRecoveryCodeDo
+	Select(conds ...field.Expr) IRecoveryCodeDo
+	Where(conds ...gen.Condition) IRecoveryCodeDo
+	Order(conds ...field.Expr) IRecoveryCodeDo
+	Distinct(cols ...field.Expr) IRecoveryCodeDo
+	Omit(cols ...field.Expr) IRecoveryCodeDo
+	Join(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo
+	Group(cols ...field.Expr) IRecoveryCodeDo
+	Having(conds ...gen.Condition) IRecoveryCodeDo
+	Limit(limit int) IRecoveryCodeDo
+	Offset(offset int) IRecoveryCodeDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IRecoveryCodeDo
+	Unscoped() IRecoveryCodeDo
+	Create(values ...*model.RecoveryCode) error
+	CreateInBatches(values []*model.RecoveryCode, batchSize int) error
+	Save(values ...*model.RecoveryCode) error
+	First() (*model.RecoveryCode, error)
+	Take() (*model.RecoveryCode, error)
+	Last() (*model.RecoveryCode, error)
+	Find() ([]*model.RecoveryCode, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RecoveryCode, err error)
+	FindInBatches(result *[]*model.RecoveryCode, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.RecoveryCode) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IRecoveryCodeDo
+	Assign(attrs ...field.AssignExpr) IRecoveryCodeDo
+	Joins(fields ...field.RelationField) IRecoveryCodeDo
+	Preload(fields ...field.RelationField) IRecoveryCodeDo
+	FirstOrInit() (*model.RecoveryCode, error)
+	FirstOrCreate() (*model.RecoveryCode, error)
+	FindByPage(offset int, limit int) (result []*model.RecoveryCode, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IRecoveryCodeDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (r recoveryCodeDo) Debug() IRecoveryCodeDo {
+	return r.withDO(r.DO.Debug())
+}
+
+func (r recoveryCodeDo) WithContext(ctx context.Context) IRecoveryCodeDo {
+	return r.withDO(r.DO.WithContext(ctx))
+}
+
+func (r recoveryCodeDo) ReadDB() IRecoveryCodeDo {
+	return r.Clauses(dbresolver.Read)
+}
+
+func (r recoveryCodeDo) WriteDB() IRecoveryCodeDo {
+	return r.Clauses(dbresolver.Write)
+}
+
+func (r recoveryCodeDo) Session(config *gorm.Session) IRecoveryCodeDo {
+	return r.withDO(r.DO.Session(config))
+}
+
+func (r recoveryCodeDo) Clauses(conds ...clause.Expression) IRecoveryCodeDo {
+	return r.withDO(r.DO.Clauses(conds...))
+}
+
+func (r recoveryCodeDo) Returning(value interface{}, columns ...string) IRecoveryCodeDo {
+	return r.withDO(r.DO.Returning(value, columns...))
+}
+
+func (r recoveryCodeDo) Not(conds ...gen.Condition) IRecoveryCodeDo {
+	return r.withDO(r.DO.Not(conds...))
+}
+
+func (r recoveryCodeDo) Or(conds ...gen.Condition) IRecoveryCodeDo {
+	return r.withDO(r.DO.Or(conds...))
+}
+
+func (r recoveryCodeDo) Select(conds ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Select(conds...))
+}
+
+func (r recoveryCodeDo) Where(conds ...gen.Condition) IRecoveryCodeDo {
+	return r.withDO(r.DO.Where(conds...))
+}
+
+func (r recoveryCodeDo) Order(conds ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Order(conds...))
+}
+
+func (r recoveryCodeDo) Distinct(cols ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Distinct(cols...))
+}
+
+func (r recoveryCodeDo) Omit(cols ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Omit(cols...))
+}
+
+func (r recoveryCodeDo) Join(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Join(table, on...))
+}
+
+func (r recoveryCodeDo) LeftJoin(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.LeftJoin(table, on...))
+}
+
+func (r recoveryCodeDo) RightJoin(table schema.Tabler, on ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.RightJoin(table, on...))
+}
+
+func (r recoveryCodeDo) Group(cols ...field.Expr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Group(cols...))
+}
+
+func (r recoveryCodeDo) Having(conds ...gen.Condition) IRecoveryCodeDo {
+	return r.withDO(r.DO.Having(conds...))
+}
+
+func (r recoveryCodeDo) Limit(limit int) IRecoveryCodeDo {
+	return r.withDO(r.DO.Limit(limit))
+}
+
+func (r recoveryCodeDo) Offset(offset int) IRecoveryCodeDo {
+	return r.withDO(r.DO.Offset(offset))
+}
+
+func (r recoveryCodeDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IRecoveryCodeDo {
+	return r.withDO(r.DO.Scopes(funcs...))
+}
+
+func (r recoveryCodeDo) Unscoped() IRecoveryCodeDo {
+	return r.withDO(r.DO.Unscoped())
+}
+
+func (r recoveryCodeDo) Create(values ...*model.RecoveryCode) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Create(values)
+}
+
+func (r recoveryCodeDo) CreateInBatches(values []*model.RecoveryCode, batchSize int) error {
+	return r.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (r recoveryCodeDo) Save(values ...*model.RecoveryCode) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Save(values)
+}
+
+func (r recoveryCodeDo) First() (*model.RecoveryCode, error) {
+	if result, err := r.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RecoveryCode), nil
+	}
+}
+
+func (r recoveryCodeDo) Take() (*model.RecoveryCode, error) {
+	if result, err := r.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RecoveryCode), nil
+	}
+}
+
+func (r recoveryCodeDo) Last() (*model.RecoveryCode, error) {
+	if result, err := r.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RecoveryCode), nil
+	}
+}
+
+func (r recoveryCodeDo) Find() ([]*model.RecoveryCode, error) {
+	result, err := r.DO.Find()
+	return result.([]*model.RecoveryCode), err
+}
+
+func (r recoveryCodeDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RecoveryCode, err error) {
+	buf := make([]*model.RecoveryCode, 0, batchSize)
+	err = r.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (r recoveryCodeDo) FindInBatches(result *[]*model.RecoveryCode, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return r.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (r recoveryCodeDo) Attrs(attrs ...field.AssignExpr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Attrs(attrs...))
+}
+
+func (r recoveryCodeDo) Assign(attrs ...field.AssignExpr) IRecoveryCodeDo {
+	return r.withDO(r.DO.Assign(attrs...))
+}
+
+func (r recoveryCodeDo) Joins(fields ...field.RelationField) IRecoveryCodeDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Joins(_f))
+	}
+	return &r
+}
+
+func (r recoveryCodeDo) Preload(fields ...field.RelationField) IRecoveryCodeDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Preload(_f))
+	}
+	return &r
+}
+
+func (r recoveryCodeDo) FirstOrInit() (*model.RecoveryCode, error) {
+	if result, err := r.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RecoveryCode), nil
+	}
+}
+
+func (r recoveryCodeDo) FirstOrCreate() (*model.RecoveryCode, error) {
+	if result, err := r.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RecoveryCode), nil
+	}
+}
+
+func (r recoveryCodeDo) FindByPage(offset int, limit int) (result []*model.RecoveryCode, count int64, err error) {
+	result, err = r.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = r.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (r recoveryCodeDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = r.Count()
+	if err != nil {
+		return
+	}
+
+	err = r.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (r recoveryCodeDo) Scan(result interface{}) (err error) {
+	return r.DO.Scan(result)
+}
+
+func (r recoveryCodeDo) Delete(models ...*model.RecoveryCode) (result gen.ResultInfo, err error) {
+	return r.DO.Delete(models)
+}
+
+func (r *recoveryCodeDo) withDO(do gen.Dao) *recoveryCodeDo {
+	r.DO = *do.(*gen.DO)
+	return r
+}
@@ -0,0 +1,414 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"tier-up/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newTokenRevocation(db *gorm.DB, opts ...gen.DOOption) tokenRevocation {
+	_tokenRevocation := tokenRevocation{}
+
+	_tokenRevocation.tokenRevocationDo.UseDB(db, opts...)
+	_tokenRevocation.tokenRevocationDo.UseModel(&model.TokenRevocation{})
+
+	tableName := _tokenRevocation.tokenRevocationDo.TableName()
+	_tokenRevocation.ALL = field.NewAsterisk(tableName)
+	_tokenRevocation.ID = field.NewUint64(tableName, "id")
+	_tokenRevocation.JTI = field.NewString(tableName, "jti")
+	_tokenRevocation.SessionID = field.NewString(tableName, "session_id")
+	_tokenRevocation.UserID = field.NewUint64(tableName, "user_id")
+	_tokenRevocation.RevokedAt = field.NewTime(tableName, "revoked_at")
+	_tokenRevocation.ExpiresAt = field.NewTime(tableName, "expires_at")
+
+	_tokenRevocation.fillFieldMap()
+
+	return _tokenRevocation
+}
+
+type tokenRevocation struct {
+	tokenRevocationDo tokenRevocationDo
+
+	ALL       field.Asterisk
+	ID        field.Uint64
+	JTI       field.String
+	SessionID field.String
+	UserID    field.Uint64
+	RevokedAt field.Time
+	ExpiresAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (t tokenRevocation) Table(newTableName string) *tokenRevocation {
+	t.tokenRevocationDo.UseTable(newTableName)
+	return t.updateTableName(newTableName)
+}
+
+func (t tokenRevocation) As(alias string) *tokenRevocation {
+	t.tokenRevocationDo.DO = *(t.tokenRevocationDo.As(alias).(*gen.DO))
+	return t.updateTableName(alias)
+}
+
+func (t *tokenRevocation) updateTableName(table string) *tokenRevocation {
+	t.ALL = field.NewAsterisk(table)
+	t.ID = field.NewUint64(table, "id")
+	t.JTI = field.NewString(table, "jti")
+	t.SessionID = field.NewString(table, "session_id")
+	t.UserID = field.NewUint64(table, "user_id")
+	t.RevokedAt = field.NewTime(table, "revoked_at")
+	t.ExpiresAt = field.NewTime(table, "expires_at")
+
+	t.fillFieldMap()
+
+	return t
+}
+
+func (t *tokenRevocation) WithContext(ctx context.Context) ITokenRevocationDo {
+	return t.tokenRevocationDo.WithContext(ctx)
+}
+
+func (t tokenRevocation) TableName() string { return t.tokenRevocationDo.TableName() }
+
+func (t tokenRevocation) Alias() string { return t.tokenRevocationDo.Alias() }
+
+func (t tokenRevocation) Columns(cols ...field.Expr) gen.Columns {
+	return t.tokenRevocationDo.Columns(cols...)
+}
+
+func (t *tokenRevocation) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := t.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (t *tokenRevocation) fillFieldMap() {
+	t.fieldMap = make(map[string]field.Expr, 6)
+	t.fieldMap["id"] = t.ID
+	t.fieldMap["jti"] = t.JTI
+	t.fieldMap["session_id"] = t.SessionID
+	t.fieldMap["user_id"] = t.UserID
+	t.fieldMap["revoked_at"] = t.RevokedAt
+	t.fieldMap["expires_at"] = t.ExpiresAt
+}
+
+func (t tokenRevocation) clone(db *gorm.DB) tokenRevocation {
+	t.tokenRevocationDo.ReplaceConnPool(db.Statement.ConnPool)
+	return t
+}
+
+func (t tokenRevocation) replaceDB(db *gorm.DB) tokenRevocation {
+	t.tokenRevocationDo.ReplaceDB(db)
+	return t
+}
+
+type tokenRevocationDo struct{ gen.DO }
+
+type ITokenRevocationDo interface {
+	gen.SubQuery
+	Debug() ITokenRevocationDo
+	WithContext(ctx context.Context) ITokenRevocationDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ITokenRevocationDo
+	WriteDB() ITokenRevocationDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ITokenRevocationDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ITokenRevocationDo
+	Not(conds ...gen.Condition) ITokenRevocationDo
+	Or(conds ...gen.Condition) ITokenRevocationDo
+	Select(conds ...field.Expr) ITokenRevocationDo
+	Where(conds ...gen.Condition) ITokenRevocationDo
+	Order(conds ...field.Expr) ITokenRevocationDo
+	Distinct(cols ...field.Expr) ITokenRevocationDo
+	Omit(cols ...field.Expr) ITokenRevocationDo
+	Join(table schema.Tabler, on ...field.Expr) ITokenRevocationDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ITokenRevocationDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ITokenRevocationDo
+	Group(cols ...field.Expr) ITokenRevocationDo
+	Having(conds ...gen.Condition) ITokenRevocationDo
+	Limit(limit int) ITokenRevocationDo
+	Offset(offset int) ITokenRevocationDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ITokenRevocationDo
+	Unscoped() ITokenRevocationDo
+	Create(values ...*model.TokenRevocation) error
+	CreateInBatches(values []*model.TokenRevocation, batchSize int) error
+	Save(values ...*model.TokenRevocation) error
+	First() (*model.TokenRevocation, error)
+	Take() (*model.TokenRevocation, error)
+	Last() (*model.TokenRevocation, error)
+	Find() ([]*model.TokenRevocation, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.TokenRevocation, err error)
+	FindInBatches(result *[]*model.TokenRevocation, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.TokenRevocation) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ITokenRevocationDo
+	Assign(attrs ...field.AssignExpr) ITokenRevocationDo
+	Joins(fields ...field.RelationField) ITokenRevocationDo
+	Preload(fields ...field.RelationField) ITokenRevocationDo
+	FirstOrInit() (*model.TokenRevocation, error)
+	FirstOrCreate() (*model.TokenRevocation, error)
+	FindByPage(offset int, limit int) (result []*model.TokenRevocation, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ITokenRevocationDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (t tokenRevocationDo) Debug() ITokenRevocationDo {
+	return t.withDO(t.DO.Debug())
+}
+
+func (t tokenRevocationDo) WithContext(ctx context.Context) ITokenRevocationDo {
+	return t.withDO(t.DO.WithContext(ctx))
+}
+
+func (t tokenRevocationDo) ReadDB() ITokenRevocationDo {
+	return t.Clauses(dbresolver.Read)
+}
+
+func (t tokenRevocationDo) WriteDB() ITokenRevocationDo {
+	return t.Clauses(dbresolver.Write)
+}
+
+func (t tokenRevocationDo) Session(config *gorm.Session) ITokenRevocationDo {
+	return t.withDO(t.DO.Session(config))
+}
+
+func (t tokenRevocationDo) Clauses(conds ...clause.Expression) ITokenRevocationDo {
+	return t.withDO(t.DO.Clauses(conds...))
+}
+
+func (t tokenRevocationDo) Returning(value interface{}, columns ...string) ITokenRevocationDo {
+	return t.withDO(t.DO.Returning(value, columns...))
+}
+
+func (t tokenRevocationDo) Not(conds ...gen.Condition) ITokenRevocationDo {
+	return t.withDO(t.DO.Not(conds...))
+}
+
+func (t tokenRevocationDo) Or(conds ...gen.Condition) ITokenRevocationDo {
+	return t.withDO(t.DO.Or(conds...))
+}
+
+func (t tokenRevocationDo) Select(conds ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Select(conds...))
+}
+
+func (t tokenRevocationDo) Where(conds ...gen.Condition) ITokenRevocationDo {
+	return t.withDO(t.DO.Where(conds...))
+}
+
+func (t tokenRevocationDo) Order(conds ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Order(conds...))
+}
+
+func (t tokenRevocationDo) Distinct(cols ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Distinct(cols...))
+}
+
+func (t tokenRevocationDo) Omit(cols ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Omit(cols...))
+}
+
+func (t tokenRevocationDo) Join(table schema.Tabler, on ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Join(table, on...))
+}
+
+func (t tokenRevocationDo) LeftJoin(table schema.Tabler, on ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.LeftJoin(table, on...))
+}
+
+func (t tokenRevocationDo) RightJoin(table schema.Tabler, on ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.RightJoin(table, on...))
+}
+
+func (t tokenRevocationDo) Group(cols ...field.Expr) ITokenRevocationDo {
+	return t.withDO(t.DO.Group(cols...))
+}
+
+func (t tokenRevocationDo) Having(conds ...gen.Condition) ITokenRevocationDo {
+	return t.withDO(t.DO.Having(conds...))
+}
+
+func (t tokenRevocationDo) Limit(limit int) ITokenRevocationDo {
+	return t.withDO(t.DO.Limit(limit))
+}
+
+func (t tokenRevocationDo) Offset(offset int) ITokenRevocationDo {
+	return t.withDO(t.DO.Offset(offset))
+}
+
+func (t tokenRevocationDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ITokenRevocationDo {
+	return t.withDO(t.DO.Scopes(funcs...))
+}
+
+func (t tokenRevocationDo) Unscoped() ITokenRevocationDo {
+	return t.withDO(t.DO.Unscoped())
+}
+
+func (t tokenRevocationDo) Create(values ...*model.TokenRevocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return t.DO.Create(values)
+}
+
+func (t tokenRevocationDo) CreateInBatches(values []*model.TokenRevocation, batchSize int) error {
+	return t.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (t tokenRevocationDo) Save(values ...*model.TokenRevocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return t.DO.Save(values)
+}
+
+func (t tokenRevocationDo) First() (*model.TokenRevocation, error) {
+	if result, err := t.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TokenRevocation), nil
+	}
+}
+
+func (t tokenRevocationDo) Take() (*model.TokenRevocation, error) {
+	if result, err := t.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TokenRevocation), nil
+	}
+}
+
+func (t tokenRevocationDo) Last() (*model.TokenRevocation, error) {
+	if result, err := t.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TokenRevocation), nil
+	}
+}
+
+func (t tokenRevocationDo) Find() ([]*model.TokenRevocation, error) {
+	result, err := t.DO.Find()
+	return result.([]*model.TokenRevocation), err
+}
+
+func (t tokenRevocationDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.TokenRevocation, err error) {
+	buf := make([]*model.TokenRevocation, 0, batchSize)
+	err = t.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (t tokenRevocationDo) FindInBatches(result *[]*model.TokenRevocation, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return t.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (t tokenRevocationDo) Attrs(attrs ...field.AssignExpr) ITokenRevocationDo {
+	return t.withDO(t.DO.Attrs(attrs...))
+}
+
+func (t tokenRevocationDo) Assign(attrs ...field.AssignExpr) ITokenRevocationDo {
+	return t.withDO(t.DO.Assign(attrs...))
+}
+
+func (t tokenRevocationDo) Joins(fields ...field.RelationField) ITokenRevocationDo {
+	for _, _f := range fields {
+		t = *t.withDO(t.DO.Joins(_f))
+	}
+	return &t
+}
+
+func (t tokenRevocationDo) Preload(fields ...field.RelationField) ITokenRevocationDo {
+	for _, _f := range fields {
+		t = *t.withDO(t.DO.Preload(_f))
+	}
+	return &t
+}
+
+func (t tokenRevocationDo) FirstOrInit() (*model.TokenRevocation, error) {
+	if result, err := t.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TokenRevocation), nil
+	}
+}
+
+func (t tokenRevocationDo) FirstOrCreate() (*model.TokenRevocation, error) {
+	if result, err := t.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TokenRevocation), nil
+	}
+}
+
+func (t tokenRevocationDo) FindByPage(offset int, limit int) (result []*model.TokenRevocation, count int64, err error) {
+	result, err = t.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = t.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (t tokenRevocationDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = t.Count()
+	if err != nil {
+		return
+	}
+
+	err = t.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (t tokenRevocationDo) Scan(result interface{}) (err error) {
+	return t.DO.Scan(result)
+}
+
+func (t tokenRevocationDo) Delete(models ...*model.TokenRevocation) (result gen.ResultInfo, err error) {
+	return t.DO.Delete(models)
+}
+
+func (t *tokenRevocationDo) withDO(do gen.Dao) *tokenRevocationDo {
+	t.DO = *do.(*gen.DO)
+	return t
+}
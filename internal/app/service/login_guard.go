@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/config"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginBlockedError 登录失败次数过多，需要等待 RetryAfter 后再试
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请 %d 秒后再试", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// LoginGuard 登录失败限制，计数保存在数据库，多个实例共享
+// 用户名不存在时同样计数，锁定状态不会暴露用户名是否存在
+type LoginGuard struct {
+	DB *gorm.DB
+
+	mu     sync.RWMutex
+	config config.LoginConfig
+	purged time.Time
+}
+
+// NewLoginGuard 创建登录失败限制
+func NewLoginGuard(cfg config.Config, db *gorm.DB) *LoginGuard {
+	return &LoginGuard{DB: db, config: cfg.Login}
+}
+
+// Update 更新配置，已有的计数和锁定保留
+func (g *LoginGuard) Update(c config.LoginConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.config = c
+}
+
+func (g *LoginGuard) cfg() config.LoginConfig {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.config
+}
+
+func userKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Attempt 验证密码或验证码之前占用一次尝试，用户名和 IP 分别计数，处于延迟或锁定期间时返回 *LoginBlockedError
+// 每次尝试先计为失败，并发的请求不会越过上限；验证通过后调用 Succeed 或 Release 归还
+func (g *LoginGuard) Attempt(ctx context.Context, username, ip string) error {
+	c := g.cfg()
+	err := repository.Transaction(ctx, g.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, g.DB)
+		if err := g.reserve(tx, c, userKey(username), c.UserMaxFailures, true); err != nil {
+			return err
+		}
+		return g.reserve(tx, c, ipKey(ip), c.IPMaxFailures, false)
+	})
+	if err != nil {
+		return err
+	}
+	g.purge(ctx, c)
+	return nil
+}
+
+// reserve 未被阻止时增加计数并计算下次允许尝试的时间，逐次延迟只用于用户名，避免共用出口 IP 的用户互相影响
+func (g *LoginGuard) reserve(tx *gorm.DB, c config.LoginConfig, key string, limit int, delay bool) error {
+	now := time.Now()
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.LoginAttempt{Key: key, LastFailedAt: now}).Error
+	if err != nil {
+		return err
+	}
+	// 检查和递增在同一条语句中完成，并发的请求按顺序计数，达到上限后的请求不再执行；上次失败超过窗口时重新计数
+	res := tx.Model(&model.LoginAttempt{}).
+		Where("attempt_key = ? AND (blocked_until IS NULL OR blocked_until <= ?)", key, now).
+		Updates(map[string]interface{}{
+			"failures":       gorm.Expr("CASE WHEN last_failed_at < ? THEN 1 ELSE failures + 1 END", now.Add(-c.Window)),
+			"last_failed_at": now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	var row model.LoginAttempt
+	if err := tx.Where("attempt_key = ?", key).First(&row).Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		wait := time.Second
+		if row.BlockedUntil != nil {
+			wait = max(row.BlockedUntil.Sub(now), wait)
+		}
+		return &LoginBlockedError{RetryAfter: wait}
+	}
+	blocked, locked := block(c, row.Failures, limit, delay)
+	if blocked == 0 {
+		return nil
+	}
+
+	updates := map[string]interface{}{"blocked_until": now.Add(blocked)}
+	if locked && row.LockedAt == nil {
+		updates["locked_at"] = now
+		slog.Warn("登录失败次数过多，已锁定", "key", key, "failures", row.Failures, "lockout", blocked)
+	}
+	return tx.Model(&model.LoginAttempt{}).Where("id = ?", row.ID).Updates(updates).Error
+}
+
+// block 失败 failures 次之后需要等待的时间，locked 表示达到上限
+func block(c config.LoginConfig, failures, limit int, delay bool) (time.Duration, bool) {
+	switch {
+	case limit > 0 && failures >= limit:
+		return c.Lockout, true
+	case delay && c.Delay > 0 && c.DelayAfter > 0 && failures >= c.DelayAfter:
+		// 逐次翻倍，最长不超过锁定时长
+		blocked := c.Delay << min(failures-c.DelayAfter, 30)
+		if c.Lockout > 0 {
+			blocked = min(blocked, c.Lockout)
+		}
+		return blocked, false
+	}
+	return 0, false
+}
+
+// Succeed 登录成功后清除用户名的计数，归还 IP 的这次尝试，IP 的其他失败保留到窗口结束
+func (g *LoginGuard) Succeed(ctx context.Context, username, ip string) error {
+	c := g.cfg()
+	return repository.Transaction(ctx, g.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, g.DB)
+		if err := tx.Where("attempt_key = ?", userKey(username)).Delete(&model.LoginAttempt{}).Error; err != nil {
+			return err
+		}
+		return g.release(tx, c, ipKey(ip), c.IPMaxFailures, false)
+	})
+}
+
+// Release 验证通过但登录尚未完成（如等待两步验证）时归还这次尝试，不清除之前的失败
+func (g *LoginGuard) Release(ctx context.Context, username, ip string) error {
+	c := g.cfg()
+	return repository.Transaction(ctx, g.DB, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, g.DB)
+		if err := g.release(tx, c, userKey(username), c.UserMaxFailures, true); err != nil {
+			return err
+		}
+		return g.release(tx, c, ipKey(ip), c.IPMaxFailures, false)
+	})
+}
+
+// release 计数减一，剩余的失败次数不需要等待时解除这次尝试设置的延迟或锁定
+func (g *LoginGuard) release(tx *gorm.DB, c config.LoginConfig, key string, limit int, delay bool) error {
+	err := tx.Model(&model.LoginAttempt{}).
+		Where("attempt_key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1")).Error
+	if err != nil {
+		return err
+	}
+	var row model.LoginAttempt
+	err = tx.Where("attempt_key = ?", key).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if blocked, _ := block(c, row.Failures, limit, delay); blocked > 0 || row.BlockedUntil == nil {
+		return nil
+	}
+	return tx.Model(&model.LoginAttempt{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+		"blocked_until": nil,
+		"locked_at":     nil,
+	}).Error
+}
+
+// Reset 解除用户名的锁定并清除计数
+func (g *LoginGuard) Reset(ctx context.Context, username string) error {
+	return repository.Conn(ctx, g.DB).Where("attempt_key = ?", userKey(username)).Delete(&model.LoginAttempt{}).Error
+}
+
+// purge 每小时删除一次已过计数窗口且未锁定的记录
+func (g *LoginGuard) purge(ctx context.Context, c config.LoginConfig) {
+	now := time.Now()
+	g.mu.Lock()
+	if now.Sub(g.purged) < time.Hour {
+		g.mu.Unlock()
+		return
+	}
+	g.purged = now
+	g.mu.Unlock()
+
+	err := g.DB.WithContext(ctx).
+		Where("last_failed_at < ? AND (blocked_until IS NULL OR blocked_until < ?)", now.Add(-c.Window), now).
+		Delete(&model.LoginAttempt{}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "清理登录失败记录失败", "error", err)
+	}
+}
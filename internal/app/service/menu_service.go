@@ -1,27 +1,31 @@
 package service
 
 import (
+	"context"
 	"tier-up/internal/app/model"
+	"tier-up/internal/repository"
 
 	"gorm.io/gorm"
 )
 
 // RoleService 角色服务
 type MenuService struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Menus *repository.Repository[model.Menu]
 }
 
 // NewRoleService 创建角色服务
-func NewMenuService(db *gorm.DB) *MenuService {
+func NewMenuService(db *gorm.DB, menus *repository.Repository[model.Menu]) *MenuService {
 	return &MenuService{
-		DB: db,
+		DB:    db,
+		Menus: menus,
 	}
 }
 
 // 菜单树
-func (m *MenuService) Tree() ([]model.Menu, error) {
-	var menus []model.Menu
-	if err := m.DB.Find(&menus).Error; err != nil {
+func (m *MenuService) Tree(ctx context.Context) ([]model.Menu, error) {
+	menus, err := m.Menus.Find(ctx)
+	if err != nil {
 		return nil, err
 	}
 	tree := buildTreeMenu(menus, nil)
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"tier-up/internal/app/middleware/jwt"
+	"tier-up/internal/app/model"
+	"tier-up/internal/config"
+	"tier-up/internal/opaque"
+	"tier-up/internal/repository"
+	"tier-up/internal/totp"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMFAAlreadyEnabled 已启用两步验证，重新绑定需要先停用
+	ErrMFAAlreadyEnabled = errors.New("两步验证已启用")
+	// ErrMFANotEnrolled 确认前没有获取密钥
+	ErrMFANotEnrolled = errors.New("请先获取两步验证密钥")
+	// ErrMFANotEnabled 未启用两步验证
+	ErrMFANotEnabled = errors.New("两步验证未启用")
+	// ErrMFARequired 角色要求两步验证，不能停用
+	ErrMFARequired = errors.New("角色要求启用两步验证，不能停用")
+	// ErrInvalidMFACode 验证码或恢复码错误
+	ErrInvalidMFACode = errors.New("验证码错误")
+	// ErrInvalidMFAToken 登录验证令牌不存在、已过期、已使用或错误次数过多
+	ErrInvalidMFAToken = errors.New("两步验证已失效，请重新登录")
+	// ErrWrongPassword 停用两步验证时密码错误
+	ErrWrongPassword = errors.New("密码错误")
+)
+
+const (
+	// mfaChallengeAttempts 每次登录允许输错验证码的次数
+	mfaChallengeAttempts = 5
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+	// totpSkew 允许前后各一个时间步的时钟误差
+	totpSkew = 1
+)
+
+// TOTPEnrollment 绑定验证器应用的信息，URI 为二维码内容，Secret 用于手动输入
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFAService 两步验证服务，使用 TOTP 验证码，恢复码用于丢失验证器时登录
+type MFAService struct {
+	DB         *gorm.DB
+	JWTService *jwt.JWTService
+	Guard      *LoginGuard
+
+	mu     sync.RWMutex
+	config config.MFAConfig
+}
+
+// NewMFAService 创建两步验证服务
+func NewMFAService(cfg config.Config, db *gorm.DB, jwtService *jwt.JWTService, guard *LoginGuard) *MFAService {
+	return &MFAService{DB: db, JWTService: jwtService, Guard: guard, config: cfg.MFA}
+}
+
+// Update 更新配置
+func (s *MFAService) Update(c config.MFAConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = c
+}
+
+func (s *MFAService) cfg() config.MFAConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// user 读取用户和角色
+func (s *MFAService) user(ctx context.Context, userID uint64) (*model.User, error) {
+	var user model.User
+	if err := repository.Conn(ctx, s.DB).Preload("Roles").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Enroll 生成新的 TOTP 密钥，确认前不生效，重复调用时替换未确认的密钥
+func (s *MFAService) Enroll(ctx context.Context, userID uint64) (*TOTPEnrollment, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.Conn(ctx, s.DB).Model(user).Update("totp_secret", secret).Error; err != nil {
+		return nil, err
+	}
+	return &TOTPEnrollment{Secret: secret, URI: totp.URI(s.cfg().Issuer, user.Username, secret)}, nil
+}
+
+// Confirm 使用验证器应用中的验证码确认密钥，启用两步验证并返回恢复码，恢复码只在此时返回
+func (s *MFAService) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	var codes []string
+	err := repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		user, err := s.user(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.MFAEnabled() {
+			return ErrMFAAlreadyEnabled
+		}
+		if user.TOTPSecret == "" {
+			return ErrMFANotEnrolled
+		}
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		tx := repository.Conn(ctx, s.DB)
+		err = tx.Model(user).Updates(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		}).Error
+		if err != nil {
+			return err
+		}
+		codes, err = newRecoveryCodes(tx, user.ID)
+		return err
+	})
+	return codes, err
+}
+
+// Disable 停用两步验证，需要密码和验证码（或恢复码），角色要求两步验证时不能停用
+func (s *MFAService) Disable(ctx context.Context, userID uint64, password, code string) error {
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		user, err := s.user(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !user.MFAEnabled() {
+			return ErrMFANotEnabled
+		}
+		if user.MFARequired() {
+			return ErrMFARequired
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+			return ErrWrongPassword
+		}
+		tx := repository.Conn(ctx, s.DB)
+		ok, err := verifyCode(tx, user, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		return clearMFA(tx, user)
+	})
+}
+
+// Reset 管理员清除用户的两步验证，用于丢失验证器和恢复码的情况
+// 角色要求两步验证时，用户下次登录需要重新绑定
+func (s *MFAService) Reset(ctx context.Context, userID uint64) error {
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		user, err := s.user(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return clearMFA(repository.Conn(ctx, s.DB), user)
+	})
+}
+
+// Challenge 密码验证通过后创建登录验证，返回令牌和有效期（秒）
+func (s *MFAService) Challenge(ctx context.Context, user *model.User) (string, int64, error) {
+	token, err := opaque.New(32)
+	if err != nil {
+		return "", 0, err
+	}
+	ttl := s.cfg().ChallengeTTL
+	now := time.Now()
+	tx := repository.Conn(ctx, s.DB)
+	// 顺便清理该用户已过期的记录
+	if err := tx.Where("user_id = ? AND expires_at < ?", user.ID, now).Delete(&model.MFAChallenge{}).Error; err != nil {
+		return "", 0, err
+	}
+	row := model.MFAChallenge{UserID: user.ID, TokenHash: opaque.Hash(token), ExpiresAt: now.Add(ttl)}
+	if err := tx.Create(&row).Error; err != nil {
+		return "", 0, err
+	}
+	return token, int64(ttl.Seconds()), nil
+}
+
+// VerifyLogin 使用登录验证令牌和验证码（或恢复码）完成登录，签发令牌
+// 验证码错误计入登录失败次数，同一令牌错误 mfaChallengeAttempts 次后失效
+func (s *MFAService) VerifyLogin(ctx context.Context, token, code string, client jwt.Client) (*jwt.TokenPair, *model.User, error) {
+	var challenge model.MFAChallenge
+	tx := repository.Conn(ctx, s.DB)
+	err := tx.Where("token_hash = ?", opaque.Hash(token)).First(&challenge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge.UsedAt != nil || !time.Now().Before(challenge.ExpiresAt) {
+		return nil, nil, ErrInvalidMFAToken
+	}
+
+	user, err := s.user(ctx, challenge.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Status != 1 || !user.MFAEnabled() {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	if err := s.Guard.Attempt(ctx, user.Username, client.IP); err != nil {
+		return nil, nil, err
+	}
+
+	// 校验验证码之前条件递增尝试次数，并发的请求不会超过 mfaChallengeAttempts 次
+	res := tx.Model(&model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL AND attempts < ?", challenge.ID, mfaChallengeAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, ErrInvalidMFAToken
+	}
+
+	ok, err := verifyCode(tx, user, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	// 条件更新保证令牌只能使用一次
+	res = tx.Model(&model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	if err := s.Guard.Succeed(ctx, user.Username, client.IP); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.JWTService.IssueTokens(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+// verifyCode 校验 TOTP 验证码或恢复码，验证码的时间步和恢复码都只能使用一次
+func verifyCode(tx *gorm.DB, user *model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok {
+			return false, nil
+		}
+		res := tx.Model(&model.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Update("totp_last_step", step)
+		return res.RowsAffected == 1, res.Error
+	}
+
+	res := tx.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, opaque.Hash(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
+
+// clearMFA 清除密钥和恢复码
+func clearMFA(tx *gorm.DB, user *model.User) error {
+	err := tx.Model(user).Updates(map[string]interface{}{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error
+}
+
+// newRecoveryCodes 替换用户的恢复码，格式为 xxxx-xxxx-xxxx-xxxx，80 位随机值
+func newRecoveryCodes(tx *gorm.DB, userID uint64) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]model.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+		rows = append(rows, model.RecoveryCode{UserID: userID, CodeHash: opaque.Hash(raw)})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和连字符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
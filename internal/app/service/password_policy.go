@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/config"
+	"tier-up/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PasswordPolicyError 密码不符合策略，Violations 为全部不满足的规则
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "密码不符合要求: " + strings.Join(e.Violations, "；")
+}
+
+// PasswordPolicy 密码策略，校验复杂度和最近使用过的密码，设置密码的过期时间
+type PasswordPolicy struct {
+	DB *gorm.DB
+
+	mu     sync.RWMutex
+	config config.PasswordConfig
+}
+
+// NewPasswordPolicy 创建密码策略
+func NewPasswordPolicy(cfg config.Config, db *gorm.DB) *PasswordPolicy {
+	return &PasswordPolicy{DB: db, config: cfg.Password}
+}
+
+// Update 更新配置，已设置的密码和过期时间不受影响
+func (p *PasswordPolicy) Update(c config.PasswordConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = c
+}
+
+func (p *PasswordPolicy) cfg() config.PasswordConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config
+}
+
+// Check 校验新密码，user.ID 不为 0 时检查当前密码和最近使用过的密码
+func (p *PasswordPolicy) Check(ctx context.Context, user *model.User, password string) error {
+	c := p.cfg()
+	var violations []string
+	if utf8.RuneCountInString(password) < c.MinLength {
+		violations = append(violations, fmt.Sprintf("长度不能少于 %d 个字符", c.MinLength))
+	}
+	// bcrypt 只使用前 72 字节
+	if len(password) > 72 {
+		violations = append(violations, "长度不能超过 72 字节")
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	if c.RequireUpper && !upper {
+		violations = append(violations, "必须包含大写字母")
+	}
+	if c.RequireLower && !lower {
+		violations = append(violations, "必须包含小写字母")
+	}
+	if c.RequireDigit && !digit {
+		violations = append(violations, "必须包含数字")
+	}
+	if c.RequireSymbol && !symbol {
+		violations = append(violations, "必须包含符号")
+	}
+	if user.Username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(user.Username)) {
+		violations = append(violations, "不能包含用户名")
+	}
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	if user.ID == 0 || c.History == 0 {
+		return nil
+	}
+	hashes, err := p.recent(ctx, user.ID, c.History)
+	if err != nil {
+		return err
+	}
+	// 启用历史记录之前设置的密码不在历史中，同样检查当前密码
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return &PasswordPolicyError{Violations: []string{fmt.Sprintf("不能与最近 %d 次使用的密码相同", c.History)}}
+		}
+	}
+	return nil
+}
+
+// Apply 设置用户密码和过期时间，expired 为 true 时立即过期，用户下次登录需要先修改密码
+// 调用前先通过 Check 校验，保存用户后调用 Record
+func (p *PasswordPolicy) Apply(user *model.User, password string, expired bool) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hash)
+	now := time.Now()
+	switch maxAge := p.cfg().MaxAge; {
+	case expired:
+		user.PasswordExpiresAt = &now
+	case maxAge > 0:
+		expiresAt := now.Add(maxAge)
+		user.PasswordExpiresAt = &expiresAt
+	default:
+		user.PasswordExpiresAt = nil
+	}
+	return nil
+}
+
+// Record 将用户当前的密码加入历史，只保留最近 History 条
+func (p *PasswordPolicy) Record(ctx context.Context, user *model.User) error {
+	tx := repository.Conn(ctx, p.DB)
+	if err := tx.Create(&model.PasswordHistory{UserID: user.ID, Hash: user.Password}).Error; err != nil {
+		return err
+	}
+	// 先查出保留的记录再删除其余的，MySQL 不支持只有 OFFSET 没有 LIMIT
+	keep := max(p.cfg().History, 1)
+	var ids []uint64
+	err := tx.Model(&model.PasswordHistory{}).
+		Where("user_id = ?", user.ID).
+		Order("id DESC").Limit(keep).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return tx.Where("user_id = ? AND id NOT IN ?", user.ID, ids).Delete(&model.PasswordHistory{}).Error
+}
+
+// recent 最近 n 个密码摘要
+func (p *PasswordPolicy) recent(ctx context.Context, userID uint64, n int) ([]string, error) {
+	var hashes []string
+	err := repository.Conn(ctx, p.DB).Model(&model.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").Limit(n).
+		Pluck("hash", &hashes).Error
+	return hashes, err
+}
@@ -1,18 +1,26 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"tier-up/internal/app/middleware/casbin"
 	"tier-up/internal/app/model"
+	"tier-up/internal/repository"
+	"tier-up/internal/route"
 
 	"gorm.io/gorm"
 )
 
 // RoleService 角色服务
 type RoleService struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	Roles    *repository.Repository[model.Role]
+	Registry *route.Registry
 }
 
 // PermissionRequest 权限请求
+// Path 为路由清单中的模板路径，如 /api/v1/user/update/:id
 type PermissionRequest struct {
 	Role   string `json:"role" binding:"required"`
 	Path   string `json:"path" binding:"required"`
@@ -20,23 +28,26 @@ type PermissionRequest struct {
 }
 
 // NewRoleService 创建角色服务
-func NewRoleService(db *gorm.DB) *RoleService {
+func NewRoleService(db *gorm.DB, roles *repository.Repository[model.Role], registry *route.Registry) *RoleService {
 	return &RoleService{
-		DB: db,
+		DB:       db,
+		Roles:    roles,
+		Registry: registry,
 	}
 }
 
 // GetRoleByID 通过ID获取角色
-func (s *RoleService) GetRoleByID(id uint) (*model.Role, error) {
-	var role model.Role
-	if err := s.DB.First(&role, id).Error; err != nil {
-		return nil, err
-	}
-	return &role, nil
+func (s *RoleService) GetRoleByID(ctx context.Context, id uint) (*model.Role, error) {
+	return s.Roles.Get(ctx, id)
 }
 
 // AddPermission 添加权限
 func (s *RoleService) AddPermission(req PermissionRequest) error {
+	req.Method = strings.ToUpper(req.Method)
+	// 只允许为已注册且需要权限验证的路由授权
+	if r, ok := s.Registry.Find(req.Method, req.Path); !ok || !r.RBAC {
+		return errors.New("路由不存在或无需权限验证")
+	}
 	cs := casbin.GetInstance()
 	_, err := cs.AddPolicy(req.Role, req.Path, req.Method)
 	return err
@@ -44,6 +55,7 @@ func (s *RoleService) AddPermission(req PermissionRequest) error {
 
 // RemovePermission 移除权限
 func (s *RoleService) RemovePermission(req PermissionRequest) error {
+	req.Method = strings.ToUpper(req.Method)
 	cs := casbin.GetInstance()
 	_, err := cs.RemovePolicy(req.Role, req.Path, req.Method)
 	return err
@@ -1,36 +1,56 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strconv"
+	"sync"
 	"tier-up/internal/app/middleware/casbin"
 	"tier-up/internal/app/middleware/jwt"
 	"tier-up/internal/app/model"
+	"tier-up/internal/app/query"
+	"tier-up/internal/repository"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// ErrInvalidCredentials 用户名不存在和密码错误返回同样的错误，避免探测用户名
+var ErrInvalidCredentials = errors.New("用户名或密码错误")
+
 type IUserService interface {
-	Register(params RegisterRequest) (*model.User, error)
-	Login(req LoginRequest) (string, *model.User, error)
-	GetUserByID(id uint) (*model.User, error)
-	UpdateUser(user *model.User) error
-	ChangePassword(userID uint, oldPassword, newPassword string) error
-	AssignRoleToUser(userID, roleID uint) error
-	RemoveRoleFromUser(userID, roleID uint) error
+	Register(ctx context.Context, params RegisterRequest) (*model.User, error)
+	Login(ctx context.Context, req LoginRequest, client jwt.Client) (*LoginResult, error)
+	RefreshToken(ctx context.Context, refreshToken string, client jwt.Client) (*jwt.TokenPair, error)
+	Logout(ctx context.Context, claims *jwt.CustomClaims) error
+	ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]SessionInfo, error)
+	RevokeSession(ctx context.Context, userID uint, id uint64) error
+	Unlock(ctx context.Context, userID uint) error
+	SetStatus(ctx context.Context, userID uint, status int) error
+	GetUserByID(ctx context.Context, id uint) (*model.User, error)
+	UpdateUser(ctx context.Context, user *model.User) error
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
+	ResetPassword(ctx context.Context, userID uint, newPassword string) error
+	AssignRoleToUser(ctx context.Context, userID, roleID uint) error
+	RemoveRoleFromUser(ctx context.Context, userID, roleID uint) error
 }
 
 // UserService 用户服务
 type UserService struct {
 	DB         *gorm.DB
 	JWTService *jwt.JWTService
+	Guard      *LoginGuard
+	Passwords  *PasswordPolicy
+	MFA        *MFAService
+	Users      *repository.Repository[model.User]
+	Roles      *repository.Repository[model.Role]
+	Query      *query.Query
 }
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
-	Password string `json:"password" binding:"required,min=6,max=100"`
+	Password string `json:"password" binding:"required,max=100"` // 复杂度由密码策略校验
 	Nickname string `json:"nickname"`
 	Email    string `json:"email" binding:"required,email"`
 	Phone    string `json:"phone"`
@@ -42,19 +62,55 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResult 登录结果，启用两步验证时只返回 MFAToken，通过 MFAService.VerifyLogin 完成登录
+type LoginResult struct {
+	Tokens       *jwt.TokenPair
+	User         *model.User
+	MFAToken     string
+	MFAExpiresIn int64 // MFAToken 有效期，秒
+}
+
+// SessionInfo 会话列表项，Current 表示发起请求的令牌所属的会话
+type SessionInfo struct {
+	model.UserSession
+	Current bool `json:"current"`
+}
+
 // NewUserService 创建用户服务
-func NewUserService(db *gorm.DB, jwtService *jwt.JWTService) *UserService {
+func NewUserService(
+	db *gorm.DB,
+	jwtService *jwt.JWTService,
+	guard *LoginGuard,
+	passwords *PasswordPolicy,
+	mfa *MFAService,
+	users *repository.Repository[model.User],
+	roles *repository.Repository[model.Role],
+	q *query.Query,
+) *UserService {
 	return &UserService{
 		DB:         db,
 		JWTService: jwtService,
+		Guard:      guard,
+		Passwords:  passwords,
+		MFA:        mfa,
+		Users:      users,
+		Roles:      roles,
+		Query:      q,
 	}
 }
 
+// q 返回使用当前上下文连接的查询对象，存在事务时在事务中执行
+func (s *UserService) q(ctx context.Context) *query.Query {
+	return s.Query.ReplaceDB(repository.Conn(ctx, s.DB))
+}
+
 // Register 注册用户
-func (s *UserService) Register(req RegisterRequest) (*model.User, error) {
+func (s *UserService) Register(ctx context.Context, req RegisterRequest) (*model.User, error) {
+	u := s.q(ctx).User
+
 	// 检查用户名是否已存在
-	var count int64
-	if err := s.DB.Model(&model.User{}).Where("username = ?", req.Username).Count(&count).Error; err != nil {
+	count, err := u.WithContext(ctx).Where(u.Username.Eq(req.Username)).Count()
+	if err != nil {
 		return nil, err
 	}
 	if count > 0 {
@@ -62,81 +118,125 @@ func (s *UserService) Register(req RegisterRequest) (*model.User, error) {
 	}
 
 	// 检查邮箱是否已存在
-	if err := s.DB.Model(&model.User{}).Where("email = ?", req.Email).Count(&count).Error; err != nil {
+	count, err = u.WithContext(ctx).Where(u.Email.Eq(req.Email)).Count()
+	if err != nil {
 		return nil, err
 	}
 	if count > 0 {
 		return nil, errors.New("邮箱已存在")
 	}
 
-	// 密码加密
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
-
 	// 创建用户
 	user := &model.User{
 		Username: req.Username,
-		Password: string(hashedPassword),
 		Nickname: req.Nickname,
 		Email:    req.Email,
 		Phone:    req.Phone,
 		Status:   1, // 默认激活状态
 	}
 
-	// 保存用户
-	if err := s.DB.Create(user).Error; err != nil {
+	// 校验密码策略并加密
+	if err := s.Passwords.Check(ctx, user, req.Password); err != nil {
+		return nil, err
+	}
+	if err := s.Passwords.Apply(user, req.Password, false); err != nil {
+		return nil, err
+	}
+
+	// 保存用户和密码历史
+	err = repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := s.Users.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.Passwords.Record(ctx, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-// Login 用户登录
-func (s *UserService) Login(req LoginRequest) (string, *model.User, error) {
-	var user model.User
+// dummyHash 用户不存在时同样比较一次密码，响应时间不暴露用户名是否存在
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("tier_up_dummy_password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Login 用户登录，记录客户端信息创建会话
+// 用户名或 IP 失败次数过多时返回 *LoginBlockedError，用户名不存在或密码错误时返回 ErrInvalidCredentials
+// 已启用两步验证时不签发令牌，返回 MFAToken；密码已过期或角色要求两步验证但尚未启用时令牌受限
+func (s *UserService) Login(ctx context.Context, req LoginRequest, client jwt.Client) (*LoginResult, error) {
+	// 先占用一次尝试再验证密码，失败时不需要再计数
+	if err := s.Guard.Attempt(ctx, req.Username, client.IP); err != nil {
+		return nil, err
+	}
 
-	// 查找用户
-	if err := s.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil, errors.New("用户不存在")
-		}
-		return "", nil, err
+	// 查找用户并验证密码
+	u := s.q(ctx).User
+	user, err := u.WithContext(ctx).Preload(u.Roles).Where(u.Username.Eq(req.Username)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	hash := dummyHash()
+	if user != nil {
+		hash = []byte(user.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || user == nil {
+		return nil, ErrInvalidCredentials
 	}
 
-	// 检查用户状态
+	// 检查用户状态，密码正确时才提示
 	if user.Status != 1 {
-		return "", nil, errors.New("用户已被禁用")
+		if err := s.Guard.Release(ctx, req.Username, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("用户已被禁用")
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return "", nil, errors.New("密码错误")
+	// 两步验证通过后才清除失败计数，验证码错误继续计数
+	if user.MFAEnabled() {
+		if err := s.Guard.Release(ctx, req.Username, client.IP); err != nil {
+			return nil, err
+		}
+		token, expiresIn, err := s.MFA.Challenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, MFAToken: token, MFAExpiresIn: expiresIn}, nil
+	}
+	if err := s.Guard.Succeed(ctx, req.Username, client.IP); err != nil {
+		return nil, err
 	}
 
-	// 生成JWT令牌
-	token, err := s.JWTService.GenerateToken(user.ID, user.Username)
+	// 生成访问令牌和刷新令牌
+	tokens, err := s.JWTService.IssueTokens(ctx, user, client)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	return token, &user, nil
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌
+func (s *UserService) RefreshToken(ctx context.Context, refreshToken string, client jwt.Client) (*jwt.TokenPair, error) {
+	return s.JWTService.Refresh(ctx, refreshToken, client)
 }
 
 // GetUserByID 通过ID获取用户信息
-func (s *UserService) GetUserByID(id uint) (*model.User, error) {
-	var user model.User
-	if err := s.DB.Preload("Roles").First(&user, id).Error; err != nil {
-		return nil, err
-	}
-	return &user, nil
+func (s *UserService) GetUserByID(ctx context.Context, id uint) (*model.User, error) {
+	return s.Users.Get(ctx, id, repository.Preload("Roles"))
+}
+
+// UpdateUser 更新用户信息
+func (s *UserService) UpdateUser(ctx context.Context, user *model.User) error {
+	return s.Users.Update(ctx, user)
 }
 
 // ChangePassword 修改密码
-func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword string) error {
-	var user model.User
-	if err := s.DB.First(&user, userID).Error; err != nil {
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
 		return err
 	}
 
@@ -145,59 +245,121 @@ func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return errors.New("旧密码错误")
 	}
 
-	// 加密新密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	return s.setPassword(ctx, user, newPassword, false)
+}
+
+// ResetPassword 管理员重置密码，用户下次登录需要先修改密码
+func (s *UserService) ResetPassword(ctx context.Context, userID uint, newPassword string) error {
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.setPassword(ctx, user, newPassword, true)
+}
+
+// setPassword 按密码策略更新密码，已签发的令牌全部失效
+func (s *UserService) setPassword(ctx context.Context, user *model.User, password string, expired bool) error {
+	if err := s.Passwords.Check(ctx, user, password); err != nil {
+		return err
+	}
+	if err := s.Passwords.Apply(user, password, expired); err != nil {
+		return err
+	}
+	return repository.Transaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := s.Users.Save(ctx, user); err != nil {
+			return err
+		}
+		if err := s.Passwords.Record(ctx, user); err != nil {
+			return err
+		}
+		return s.JWTService.RevokeUser(ctx, user.ID)
+	})
+}
+
+// Logout 退出登录，终止当前会话
+func (s *UserService) Logout(ctx context.Context, claims *jwt.CustomClaims) error {
+	return s.JWTService.Logout(ctx, claims)
+}
+
+// ListSessions 用户的有效会话
+func (s *UserService) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]SessionInfo, error) {
+	sessions, err := s.JWTService.Sessions(ctx, uint64(userID))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, SessionInfo{UserSession: session, Current: currentSessionID != "" && session.SessionID == currentSessionID})
+	}
+	return list, nil
+}
+
+// RevokeSession 终止用户的会话，会话不属于该用户时返回 gorm.ErrRecordNotFound
+func (s *UserService) RevokeSession(ctx context.Context, userID uint, id uint64) error {
+	return s.JWTService.RevokeSession(ctx, uint64(userID), id)
+}
+
+// SetStatus 修改用户状态，1 为正常，其他值为禁用，禁用后已签发的令牌失效
+func (s *UserService) SetStatus(ctx context.Context, userID uint, status int) error {
+	user, err := s.Users.Get(ctx, userID)
 	if err != nil {
 		return err
 	}
+	// 通过 Update 写入零值，禁用时由 JWTService 注册的回调吊销令牌
+	return repository.Conn(ctx, s.DB).Model(user).Update("status", status).Error
+}
 
-	// 更新密码
-	user.Password = string(hashedPassword)
-	return s.DB.Save(&user).Error
+// Unlock 解除用户的登录锁定
+func (s *UserService) Unlock(ctx context.Context, userID uint) error {
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.Guard.Reset(ctx, user.Username)
 }
 
 // AssignRoleToUser 给用户分配角色
-func (s *UserService) AssignRoleToUser(userID, roleID uint) error {
-	var user model.User
-	if err := s.DB.First(&user, userID).Error; err != nil {
+func (s *UserService) AssignRoleToUser(ctx context.Context, userID, roleID uint) error {
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
 		return err
 	}
 
-	var role model.Role
-	if err := s.DB.First(&role, roleID).Error; err != nil {
+	role, err := s.Roles.Get(ctx, roleID)
+	if err != nil {
 		return err
 	}
 
 	// 添加角色关联
-	if err := s.DB.Model(&user).Association("Roles").Append(&role); err != nil {
+	if err := s.Users.Conn(ctx).Model(user).Association("Roles").Append(role); err != nil {
 		return err
 	}
 
 	// 同时添加Casbin规则
 	cs := casbin.GetInstance()
-	_, err := cs.AddRoleForUser(strconv.Itoa(int(userID)), role.Name)
+	_, err = cs.AddRoleForUser(strconv.Itoa(int(userID)), role.Name)
 	return err
 }
 
 // RemoveRoleFromUser 从用户移除角色
-func (s *UserService) RemoveRoleFromUser(userID, roleID uint) error {
-	var user model.User
-	if err := s.DB.First(&user, userID).Error; err != nil {
+func (s *UserService) RemoveRoleFromUser(ctx context.Context, userID, roleID uint) error {
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
 		return err
 	}
 
-	var role model.Role
-	if err := s.DB.First(&role, roleID).Error; err != nil {
+	role, err := s.Roles.Get(ctx, roleID)
+	if err != nil {
 		return err
 	}
 
 	// 移除角色关联
-	if err := s.DB.Model(&user).Association("Roles").Delete(&role); err != nil {
+	if err := s.Users.Conn(ctx).Model(user).Association("Roles").Delete(role); err != nil {
 		return err
 	}
 
 	// 同时移除Casbin规则
 	cs := casbin.GetInstance()
-	_, err := cs.DeleteRoleForUser(strconv.Itoa(int(userID)), role.Name)
+	_, err = cs.DeleteRoleForUser(strconv.Itoa(int(userID)), role.Name)
 	return err
 }
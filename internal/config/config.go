@@ -0,0 +1,185 @@
+package config
+
+import "time"
+
+// Config 应用配置，由 Load 从 config.yaml、config.<APP_ENV>.yaml 和环境变量读取
+// Logging.Level、CORS、JWT.AccessTTL、JWT.RefreshTTL、RateLimit、Login、Password、MFA 修改配置文件后无需重启，见 Manager
+type Config struct {
+	Env         string            `mapstructure:"APP_ENV"` // 运行环境 dev | prod，来自环境变量 APP_ENV
+	Server      ServerConfig      `mapstructure:"Server"`
+	DB          DbConfig          `mapstructure:"DB"`
+	JWT         JWTConfig         `mapstructure:"JWT"`
+	CORS        CORSConfig        `mapstructure:"CORS"`
+	Logging     LoggingConfig     `mapstructure:"Logging"`
+	RateLimit   RateLimitConfig   `mapstructure:"RateLimit"`
+	Login       LoginConfig       `mapstructure:"Login"`
+	Password    PasswordConfig    `mapstructure:"Password"`
+	MFA         MFAConfig         `mapstructure:"MFA"`
+	Idempotency IdempotencyConfig `mapstructure:"Idempotency"`
+	Seed        SeedConfig        `mapstructure:"Seed"`
+}
+
+// IsProd 是否为生产环境
+func (c Config) IsProd() bool {
+	return c.Env == "prod" || c.Env == "production"
+}
+
+// ServerConfig HTTP 服务配置
+type ServerConfig struct {
+	Host            string        `mapstructure:"Host"`
+	Port            string        `mapstructure:"Port"`
+	Mode            string        `mapstructure:"Mode"`            // gin 模式 debug | release | test
+	ReadTimeout     time.Duration `mapstructure:"ReadTimeout"`     // 读取请求的超时时间
+	WriteTimeout    time.Duration `mapstructure:"WriteTimeout"`    // 写入响应的超时时间
+	ShutdownTimeout time.Duration `mapstructure:"ShutdownTimeout"` // 优雅退出时等待请求完成的时间
+	// TrustedProxies 反向代理的 IP 或 CIDR，只有来自这些地址的请求才读取 X-Forwarded-For 作为客户端 IP
+	// 为空时不信任任何代理，客户端 IP 为连接的对端地址
+	TrustedProxies []string `mapstructure:"TrustedProxies"`
+}
+
+// Addr 监听地址
+func (s ServerConfig) Addr() string {
+	return s.Host + ":" + s.Port
+}
+
+// DbConfig 数据库链接配置
+// Driver 可选 postgres、mysql、sqlserver、sqlite，DriverName 为数据库名，SQLite 为文件路径或 :memory:
+type DbConfig struct {
+	AutoCreateTable bool   `mapstructure:"AutoCreateTable"` // 按模型自动建表，仅用于开发环境
+	MigrateOnStart  bool   `mapstructure:"MigrateOnStart"`  // 启动时执行版本化迁移
+	Driver          string `mapstructure:"Driver"`
+	DriverName      string `mapstructure:"DriverName"`
+	Host            string `mapstructure:"Host"`
+	User            string `mapstructure:"User"`
+	Password        string `mapstructure:"Password"`
+	Port            string `mapstructure:"Port"`
+	Charset         string `mapstructure:"Charset"`
+	TimeZone        string `mapstructure:"TimeZone"` // 连接时区，Postgres 为 TimeZone，MySQL 为 loc
+	DSN             string `mapstructure:"DSN"`      // 完整连接字符串，设置后忽略以上连接参数
+
+	Pool DbPoolConfig `mapstructure:"Pool"` // 连接池
+	Log  DbLogConfig  `mapstructure:"Log"`  // SQL 日志
+
+	Replicas      []DbNode         `mapstructure:"Replicas"`      // 只读副本
+	ReplicaPolicy string           `mapstructure:"ReplicaPolicy"` // 副本选择策略 random | round_robin
+	Resolvers     []ResolverConfig `mapstructure:"Resolvers"`     // 按表指定主库和副本
+}
+
+// DbPoolConfig 连接池配置，副本使用相同的配置，为 0 的项使用 database/sql 的默认值
+type DbPoolConfig struct {
+	MaxIdleConns    int           `mapstructure:"MaxIdleConns"`    // 空闲连接池中连接的最大数量
+	MaxOpenConns    int           `mapstructure:"MaxOpenConns"`    // 打开数据库连接的最大数量
+	ConnMaxLifetime time.Duration `mapstructure:"ConnMaxLifetime"` // 连接可复用的最大时间
+	ConnMaxIdleTime time.Duration `mapstructure:"ConnMaxIdleTime"` // 连接空闲的最大时间
+}
+
+// DbLogConfig SQL 日志配置
+type DbLogConfig struct {
+	Level         string        `mapstructure:"Level"`         // silent | error | warn | info，info 记录全部 SQL
+	SlowThreshold time.Duration `mapstructure:"SlowThreshold"` // 慢查询阈值，超过时以 warn 级别记录，0 为不检测
+	HideParams    bool          `mapstructure:"HideParams"`    // 日志中的 SQL 不包含参数值
+}
+
+// DbNode 主库或副本的连接参数，未填写的字段使用 DB 中的配置
+type DbNode struct {
+	Host       string `mapstructure:"Host"`
+	Port       string `mapstructure:"Port"`
+	User       string `mapstructure:"User"`
+	Password   string `mapstructure:"Password"`
+	DriverName string `mapstructure:"DriverName"`
+	DSN        string `mapstructure:"DSN"`
+}
+
+// ResolverConfig 指定表的读写分离，表不使用全局的副本
+type ResolverConfig struct {
+	Tables   []string `mapstructure:"Tables"`   // 模型对应的表名
+	Sources  []DbNode `mapstructure:"Sources"`  // 主库，为空时使用 DB 的主库
+	Replicas []DbNode `mapstructure:"Replicas"` // 副本，为空时读主库
+	Policy   string   `mapstructure:"Policy"`   // random | round_robin，默认 ReplicaPolicy
+}
+
+// JWTConfig 令牌配置
+// 配置了 Keys 时使用其中已生效的最新密钥签名，否则使用 Secret 以 HS256 签名
+// 密钥由 Secret 或 SecretFile 提供，生产环境不使用 Keys 时不能为空或使用默认密钥
+type JWTConfig struct {
+	Secret     string        `mapstructure:"Secret"`     // HS256 签名密钥，生产环境通过 JWT_SECRET 提供
+	SecretFile string        `mapstructure:"SecretFile"` // 从文件读取签名密钥，如挂载的 Kubernetes Secret，设置后忽略 Secret
+	Issuer     string        `mapstructure:"Issuer"`     // 签发者，校验令牌时要求一致
+	Audience   string        `mapstructure:"Audience"`   // 接收方，为空时不签发也不校验
+	AccessTTL  time.Duration `mapstructure:"AccessTTL"`  // 访问令牌有效期
+	RefreshTTL time.Duration `mapstructure:"RefreshTTL"` // 刷新令牌有效期，每次刷新后重新计算
+	ClockSkew  time.Duration `mapstructure:"ClockSkew"`  // 校验过期和生效时间时允许的时钟误差
+	Keys       []JWTKey      `mapstructure:"Keys"`       // 非对称签名密钥，公钥通过 /.well-known/jwks.json 发布
+}
+
+// JWTKey 非对称签名密钥，RSA 密钥使用 RS256，Ed25519 密钥使用 EdDSA
+// 到达 ActiveFrom 后用于签名，之前已发布公钥供其他服务提前获取；被新密钥替换后继续用于验证，直到从配置中删除
+type JWTKey struct {
+	ID         string    `mapstructure:"ID"`         // kid
+	File       string    `mapstructure:"File"`       // PEM 格式的私钥文件，PKCS#8 或 PKCS#1
+	ActiveFrom time.Time `mapstructure:"ActiveFrom"` // 开始用于签名的时间，为空时立即生效
+}
+
+// CORSConfig 跨域配置，AllowOrigins 为空时不处理跨域请求
+type CORSConfig struct {
+	AllowOrigins     []string      `mapstructure:"AllowOrigins"` // 允许的来源，* 为全部
+	AllowMethods     []string      `mapstructure:"AllowMethods"`
+	AllowHeaders     []string      `mapstructure:"AllowHeaders"`
+	ExposeHeaders    []string      `mapstructure:"ExposeHeaders"`
+	AllowCredentials bool          `mapstructure:"AllowCredentials"` // 为 true 时 AllowOrigins 不能为 *
+	MaxAge           time.Duration `mapstructure:"MaxAge"`           // 预检请求的缓存时间
+}
+
+// LoggingConfig 应用日志配置
+type LoggingConfig struct {
+	Level  string `mapstructure:"Level"`  // debug | info | warn | error
+	Format string `mapstructure:"Format"` // json | text
+}
+
+// RateLimitConfig 按客户端 IP 限流，令牌桶每秒补充 Rate 个，最多 Burst 个，Rate 为 0 时不限流
+type RateLimitConfig struct {
+	Rate  float64 `mapstructure:"Rate"`  // 每秒请求数
+	Burst int     `mapstructure:"Burst"` // 允许的突发请求数，为 0 时与 Rate 相同
+}
+
+// LoginConfig 登录失败限制，按用户名和客户端 IP 分别计数
+// 同一用户名失败 DelayAfter 次后每次失败需要等待 Delay（逐次翻倍，最长 Lockout）才能再次尝试，
+// 达到 UserMaxFailures 或 IPMaxFailures 次后锁定 Lockout，最后一次失败 Window 之后重新计数
+type LoginConfig struct {
+	UserMaxFailures int           `mapstructure:"UserMaxFailures"` // 同一用户名的失败次数上限，0 为不锁定
+	IPMaxFailures   int           `mapstructure:"IPMaxFailures"`   // 同一 IP 的失败次数上限，0 为不锁定
+	DelayAfter      int           `mapstructure:"DelayAfter"`      // 开始延迟的失败次数
+	Delay           time.Duration `mapstructure:"Delay"`           // 首次延迟，0 为不延迟
+	Lockout         time.Duration `mapstructure:"Lockout"`         // 锁定时长
+	Window          time.Duration `mapstructure:"Window"`          // 失败计数窗口
+}
+
+// PasswordConfig 密码策略，用于注册、修改密码和管理员重置密码
+type PasswordConfig struct {
+	MinLength     int           `mapstructure:"MinLength"`     // 最小长度，按字符计算
+	RequireUpper  bool          `mapstructure:"RequireUpper"`  // 必须包含大写字母
+	RequireLower  bool          `mapstructure:"RequireLower"`  // 必须包含小写字母
+	RequireDigit  bool          `mapstructure:"RequireDigit"`  // 必须包含数字
+	RequireSymbol bool          `mapstructure:"RequireSymbol"` // 必须包含字母和数字以外的字符
+	History       int           `mapstructure:"History"`       // 不能与最近 N 个密码相同，0 为不限制
+	MaxAge        time.Duration `mapstructure:"MaxAge"`        // 密码有效期，到期后登录需要先修改密码，0 为不过期
+}
+
+// MFAConfig 两步验证配置
+type MFAConfig struct {
+	Issuer       string        `mapstructure:"Issuer"`       // 验证器应用中显示的发行方
+	ChallengeTTL time.Duration `mapstructure:"ChallengeTTL"` // 密码验证通过后完成两步验证的期限
+}
+
+// IdempotencyConfig 幂等请求配置
+type IdempotencyConfig struct {
+	Store string        `mapstructure:"Store"` // 存储方式: memory | db
+	TTL   time.Duration `mapstructure:"TTL"`   // 响应保留时长
+	Lease time.Duration `mapstructure:"Lease"` // 请求处理中占用key的时长，到期未完成时允许重试，应大于请求的最长处理时间
+}
+
+// SeedConfig 初始数据配置
+type SeedConfig struct {
+	OnStart bool   `mapstructure:"OnStart"` // 启动时写入初始数据
+	Dir     string `mapstructure:"Dir"`     // seed 文件目录，读取 seed.yaml 和 seed.<APP_ENV>.yaml
+}
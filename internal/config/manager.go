@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Manager 持有当前生效的配置，配置文件修改后重新读取并通知订阅者
+// 新配置校验失败时记录错误并继续使用上一次有效的配置
+type Manager struct {
+	dir string
+
+	// reload 串行化 Reload，读取、比较和通知期间不会有另一次重新读取
+	// 否则先读到的旧文件可能在新文件之后生效
+	reload sync.Mutex
+
+	mu   sync.RWMutex
+	cfg  Config
+	subs []func(old, cur Config)
+}
+
+// NewManager 创建配置管理器，c 为已从 dir 读取的配置
+func NewManager(dir string, c Config) *Manager {
+	return &Manager{dir: dir, cfg: c}
+}
+
+// Get 当前配置
+func (m *Manager) Get() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// OnChange 订阅配置变化，重新读取的配置与当前不同时按订阅顺序调用
+// 回调中不能再调用 OnChange 或 Reload
+func (m *Manager) OnChange(fn func(old, cur Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs = append(m.subs, fn)
+}
+
+// Reload 重新读取配置，失败时保留当前配置并返回错误
+func (m *Manager) Reload() error {
+	m.reload.Lock()
+	defer m.reload.Unlock()
+
+	cur, _, err := LoadDir(m.dir)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := m.cfg
+	if reflect.DeepEqual(old, cur) {
+		return nil
+	}
+	m.cfg = cur
+	if fields := restartRequired(old, cur); len(fields) > 0 {
+		slog.Warn("配置已修改，需要重启生效", "fields", fields)
+	}
+	for _, fn := range m.subs {
+		fn(old, cur)
+	}
+	return nil
+}
+
+// Watch 监听配置文件，修改后调用 Reload，直到 ctx 结束
+// 监听目录而不是文件，编辑器保存时替换文件也能收到事件
+func (m *Manager) Watch(ctx context.Context) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dir := m.dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return fmt.Errorf("监听配置目录失败: %w", err)
+	}
+
+	go func() {
+		defer w.Close()
+		// 保存文件时会连续产生多个事件，合并后只读取一次
+		var timer *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("监听配置文件失败", "error", err)
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if !m.watched(filepath.Base(e.Name)) || !e.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(200*time.Millisecond, m.reloadAndLog)
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *Manager) reloadAndLog() {
+	if err := m.Reload(); err != nil {
+		slog.Error("重新读取配置失败，继续使用当前配置", "error", err)
+		return
+	}
+	slog.Info("配置已重新读取")
+}
+
+// watched 是否为配置文件，环境变化需要重启，只监听当前环境的文件
+func (m *Manager) watched(name string) bool {
+	return name == "config.yaml" || name == "config."+m.Get().Env+".yaml"
+}
+
+// restartRequired 修改后需要重启才能生效的配置
+func restartRequired(old, cur Config) []string {
+	var fields []string
+	add := func(name string, a, b any) {
+		if !reflect.DeepEqual(a, b) {
+			fields = append(fields, name)
+		}
+	}
+	add("Server", old.Server, cur.Server)
+	add("DB", old.DB, cur.DB)
+	add("JWT.Secret", old.JWT.Secret, cur.JWT.Secret)
+	add("JWT.Issuer", old.JWT.Issuer, cur.JWT.Issuer)
+	add("JWT.Audience", old.JWT.Audience, cur.JWT.Audience)
+	add("JWT.ClockSkew", old.JWT.ClockSkew, cur.JWT.ClockSkew)
+	add("Logging.Format", old.Logging.Format, cur.Logging.Format)
+	add("Idempotency", old.Idempotency, cur.Idempotency)
+	add("Seed", old.Seed, cur.Seed)
+	return fields
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate 校验配置，返回全部错误
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port < 65536, "Server.Port 无效: %q", c.Server.Port)
+	check(oneOf(c.Server.Mode, "debug", "release", "test"), "Server.Mode 可选 debug、release、test，当前为 %q", c.Server.Mode)
+	check(c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.ShutdownTimeout >= 0, "Server 的超时时间不能为负数")
+	for i, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "Server.TrustedProxies[%d] 不是有效的 IP 或 CIDR: %q", i, proxy)
+	}
+
+	// SQLite 的 DriverName 为空时为内存数据库
+	check(c.DB.DSN != "" || c.DB.DriverName != "" || strings.HasPrefix(strings.ToLower(c.DB.Driver), "sqlite"), "DB.DriverName 不能为空")
+	check(c.DB.Pool.MaxIdleConns >= 0 && c.DB.Pool.MaxOpenConns >= 0, "DB.Pool 的连接数不能为负数")
+	check(c.DB.Pool.MaxOpenConns == 0 || c.DB.Pool.MaxIdleConns <= c.DB.Pool.MaxOpenConns, "DB.Pool.MaxIdleConns 不能大于 MaxOpenConns")
+	check(oneOf(strings.ToLower(c.DB.Log.Level), "silent", "error", "warn", "info"), "DB.Log.Level 可选 silent、error、warn、info，当前为 %q", c.DB.Log.Level)
+	check(oneOf(c.DB.ReplicaPolicy, "", "random", "round_robin", "round-robin"), "DB.ReplicaPolicy 可选 random、round_robin，当前为 %q", c.DB.ReplicaPolicy)
+
+	check(c.JWT.AccessTTL > 0, "JWT.AccessTTL 必须大于 0")
+	check(c.JWT.RefreshTTL > c.JWT.AccessTTL, "JWT.RefreshTTL 必须大于 AccessTTL")
+	check(c.JWT.ClockSkew >= 0, "JWT.ClockSkew 不能为负数")
+	if c.IsProd() {
+		// 密钥都未到 ActiveFrom 时没有可用于签名的密钥
+		now := time.Now()
+		signable := c.JWT.Secret != ""
+		for _, k := range c.JWT.Keys {
+			signable = signable || !k.ActiveFrom.After(now)
+		}
+		check(signable, "生产环境必须通过 JWT_SECRET、JWT.SecretFile 配置签名密钥，或在 JWT.Keys 中配置已到 ActiveFrom 的密钥")
+		check(c.JWT.Secret != DefaultJWTSecret, "生产环境不能使用默认的 JWT 签名密钥")
+	}
+	kids := map[string]bool{}
+	for i, k := range c.JWT.Keys {
+		check(k.ID != "" && k.File != "", "JWT.Keys[%d] 的 ID 和 File 不能为空", i)
+		check(!kids[k.ID], "JWT.Keys 的 ID %q 重复", k.ID)
+		kids[k.ID] = true
+	}
+
+	check(c.RateLimit.Rate >= 0 && c.RateLimit.Burst >= 0, "RateLimit.Rate 和 Burst 不能为负数")
+
+	check(c.Login.UserMaxFailures >= 0 && c.Login.IPMaxFailures >= 0 && c.Login.DelayAfter >= 0, "Login 的失败次数不能为负数")
+	check(c.Login.Delay >= 0, "Login.Delay 不能为负数")
+	check(c.Login.UserMaxFailures == 0 && c.Login.IPMaxFailures == 0 || c.Login.Lockout > 0, "Login.Lockout 必须大于 0")
+	check(c.Login.Window > 0, "Login.Window 必须大于 0")
+
+	check(c.Password.MinLength >= 1 && c.Password.MinLength <= 72, "Password.MinLength 必须在 1 到 72 之间")
+	check(c.Password.History >= 0 && c.Password.MaxAge >= 0, "Password.History 和 MaxAge 不能为负数")
+
+	check(c.MFA.Issuer != "", "MFA.Issuer 不能为空")
+	check(c.MFA.ChallengeTTL > 0, "MFA.ChallengeTTL 必须大于 0")
+
+	for _, origin := range c.CORS.AllowOrigins {
+		check(!(origin == "*" && c.CORS.AllowCredentials), "CORS.AllowCredentials 为 true 时 AllowOrigins 不能为 *")
+	}
+
+	check(oneOf(strings.ToLower(c.Logging.Level), "debug", "info", "warn", "error"), "Logging.Level 可选 debug、info、warn、error，当前为 %q", c.Logging.Level)
+	check(oneOf(c.Logging.Format, "json", "text"), "Logging.Format 可选 json、text，当前为 %q", c.Logging.Format)
+
+	check(oneOf(c.Idempotency.Store, "memory", "db"), "Idempotency.Store 可选 memory、db，当前为 %q", c.Idempotency.Store)
+	check(c.Idempotency.TTL > 0, "Idempotency.TTL 必须大于 0")
+	check(c.Idempotency.Lease > 0, "Idempotency.Lease 必须大于 0")
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("配置校验失败:\n%w", err)
+	}
+	return nil
+}
+
+func oneOf(s string, options ...string) bool {
+	for _, o := range options {
+		if s == o {
+			return true
+		}
+	}
+	return false
+}
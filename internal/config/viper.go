@@ -1,81 +1,190 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
-// DbConfig MySQL 链接配置
-// 字段 类型 员信息
-type DbConfig struct {
-	AutoCreateTable bool   `yaml:"AutoCreateTable"`
-	Driver          string `yaml:"Driver"`
-	DriverName      string `yaml:"DriverName"`
-	Host            string `yaml:"Host"`
-	User            string `yaml:"User"`
-	Password        string `yaml:"Password"`
-	Port            string `yaml:"Port"`
-	Charset         string `yaml:"Charset"`
+// defaults 默认值，配置文件缺少的项使用这里的值
+var defaults = map[string]interface{}{
+	"Server.Host":            "0.0.0.0",
+	"Server.Port":            "88",
+	"Server.Mode":            "debug",
+	"Server.ReadTimeout":     "15s",
+	"Server.WriteTimeout":    "30s",
+	"Server.ShutdownTimeout": "10s",
+
+	"DB.Driver":               "postgres",
+	"DB.Port":                 "5432",
+	"DB.Charset":              "utf8mb4",
+	"DB.TimeZone":             "Asia/Shanghai",
+	"DB.ReplicaPolicy":        "random",
+	"DB.Pool.MaxIdleConns":    10,
+	"DB.Pool.MaxOpenConns":    100,
+	"DB.Pool.ConnMaxLifetime": "1h",
+	"DB.Log.Level":            "warn",
+	"DB.Log.SlowThreshold":    "200ms",
+
+	"JWT.Issuer":     "tier_up",
+	"JWT.AccessTTL":  "15m",
+	"JWT.RefreshTTL": "720h",
+	"JWT.ClockSkew":  "30s",
+
+	"CORS.AllowMethods":  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+	"CORS.AllowHeaders":  []string{"Authorization", "Content-Type", "Idempotency-Key", "X-Request-ID"},
+	"CORS.ExposeHeaders": []string{"X-Request-ID"},
+	"CORS.MaxAge":        "12h",
+
+	"Logging.Level":  "info",
+	"Logging.Format": "json",
+
+	"Login.UserMaxFailures": 5,
+	"Login.IPMaxFailures":   20,
+	"Login.DelayAfter":      3,
+	"Login.Delay":           "1s",
+	"Login.Lockout":         "15m",
+	"Login.Window":          "15m",
+
+	"Password.MinLength":    8,
+	"Password.RequireUpper": true,
+	"Password.RequireLower": true,
+	"Password.RequireDigit": true,
+	"Password.History":      5,
+	"Password.MaxAge":       "2160h",
+
+	"MFA.Issuer":       "Tier Up",
+	"MFA.ChallengeTTL": "5m",
+
+	"Idempotency.Store": "memory",
+	"Idempotency.TTL":   "24h",
+	"Idempotency.Lease": "1m",
+
+	"Seed.Dir": "seeds",
 }
 
-type WebConfig struct {
-	Host string `yaml:"host"`
-	Port string `yaml:"port"`
+// DefaultJWTSecret 默认签名密钥，仅用于开发环境，生产环境使用时校验失败
+const DefaultJWTSecret = "tier_up_secret_key"
+
+// Load 从工作目录读取配置，见 LoadDir
+func Load() (Config, error) {
+	c, _, err := LoadDir(".")
+	return c, err
 }
-type Config struct {
-	DB     DbConfig  `mapstructure:"DB"`
-	WebApi WebConfig `mapstructure:"WebApi"`
+
+// MustLoad 读取配置，失败时退出，用于命令行工具
+func MustLoad() Config {
+	c, err := Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "配置错误:", err)
+		os.Exit(1)
+	}
+	return c
 }
 
-func (d *Config) InitConfig() {
-	// 加载 .env 环境变量文件
-	_ = godotenv.Load()
+// LoadDir 读取 dir 中的配置，返回读取到的配置文件
+// 依次读取 config.yaml 和 config.<APP_ENV>.yaml（APP_ENV 默认 dev），后者覆盖前者，文件不存在时跳过
+// 环境变量覆盖配置文件，名称为键路径大写并以 _ 连接，如 DB_PASSWORD、SERVER_PORT、JWT_SECRET
+// 工作目录中的 .env 文件会先加载到环境变量
+func LoadDir(dir string) (Config, []string, error) {
+	_ = godotenv.Load(filepath.Join(dir, ".env"))
+
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "dev"
+	}
 
-	viper.SetConfigFile("tier-up/config.yaml") // 指定配置文件路径
-	viper.SetConfigName("config")              // 配置文件名称(无扩展名)
-	viper.SetConfigType("yaml")                // 如果配置文件的名称中没有扩展名，则需要配置此项
+	v := viper.New()
+	v.SetConfigType("yaml")
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
 
-	viper.AddConfigPath(".") // 还可以在工作目录中查找配置
-	viper.WatchConfig()      //监控配置文件更新
+	var files []string
+	for _, name := range []string{"config.yaml", "config." + env + ".yaml"} {
+		path := filepath.Join(dir, name)
+		f, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Config{}, nil, err
+		}
+		err = v.MergeConfig(f)
+		f.Close()
+		if err != nil {
+			return Config{}, nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+		}
+		files = append(files, path)
+	}
 
-	err := viper.ReadInConfig() // 查找并读取配置文件
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	// 配置文件中没有的键也能由环境变量设置
+	bindEnvs(v, reflect.TypeOf(Config{}), "")
 
-	viper.AutomaticEnv()                                   // 自动查找环境变量
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // app.port → APP_PORT
-	// viper 会自动映射 同名变量
-	if err != nil { // 处理读取配置文件的错误
-		panic(fmt.Errorf("fatal error config file %v", err))
+	var c Config
+	// 在 viper 默认的时长、列表转换之外支持 RFC 3339 时间
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	))
+	if err := v.Unmarshal(&c, hook); err != nil {
+		return Config{}, files, fmt.Errorf("解析配置失败: %w", err)
 	}
-	// 读取配置文件
-	autoCreateTable := viper.GetBool("DB.AutoCreateTable")
-	user := viper.GetString("DB.User")
-	password := viper.GetString("DB.Password")
-	host := viper.GetString("DB.Host")
-	port := viper.GetString("DB.Port")
-	driverName := viper.GetString("DB.DriverName")
-	charset := viper.GetString("DB.Charset")
-
-	d.DB = DbConfig{
-		AutoCreateTable: autoCreateTable,
-		User:            user,
-		Password:        password,
-		Port:            port,
-		Host:            host,
-		DriverName:      driverName,
-		Charset:         charset,
+	c.Env = env
+	if err := loadJWTSecret(&c); err != nil {
+		return c, files, err
 	}
-	d.WebApi = WebConfig{
-		Host: viper.GetString("WebApi.Host"),
-		Port: viper.GetString("WebApi.Port"),
+	if err := c.Validate(); err != nil {
+		return c, files, err
 	}
+	return c, files, nil
+}
 
+// loadJWTSecret 读取 JWT.SecretFile，开发环境未配置密钥时使用默认密钥
+func loadJWTSecret(c *Config) error {
+	if c.JWT.SecretFile != "" {
+		b, err := os.ReadFile(c.JWT.SecretFile)
+		if err != nil {
+			return fmt.Errorf("读取 JWT.SecretFile 失败: %w", err)
+		}
+		c.JWT.Secret = strings.TrimSpace(string(b))
+	}
+	if c.JWT.Secret == "" && !c.IsProd() {
+		c.JWT.Secret = DefaultJWTSecret
+	}
+	return nil
 }
 
-func Load() Config {
-	var c Config
-	c.InitConfig()
-	return c
+// bindEnvs 为结构体的每个字段绑定环境变量，列表中的结构体只能在配置文件中设置
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("mapstructure")
+		if key == "" {
+			key = f.Name
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if f.Type.Kind() == reflect.Struct {
+			bindEnvs(v, f.Type, key)
+			continue
+		}
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct {
+			continue
+		}
+		_ = v.BindEnv(key)
+	}
 }
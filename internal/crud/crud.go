@@ -1,25 +1,39 @@
 package crud
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
+
+	"tier-up/internal/repository"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type ICrud[T any] interface {
 	Create(*gin.Context)
 	Update(*gin.Context)
 	Delete(*gin.Context)
+	DeleteByKey(*gin.Context)
 	Page(*gin.Context)
 }
 
+// Crud 通用增删改查接口，数据访问由 Repository 完成
 type Crud[T any, CreateDTO any] struct {
-	DB *gorm.DB
+	Repo *repository.Repository[T]
+	Keys []*schema.Field // 主键字段，支持复合主键
 }
 
+// 分页查询保留的参数，其余参数作为过滤条件
+var reservedQuery = map[string]bool{"page": true, "limit": true, "sort": true}
+
 func (c Crud[T, CreateDTO]) Create(ctx *gin.Context) {
 	var dto CreateDTO
 
@@ -34,7 +48,7 @@ func (c Crud[T, CreateDTO]) Create(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "参数错误: " + err.Error()})
 		return
 	}
-	if err := c.DB.Create(&entity).Error; err != nil {
+	if err := c.Repo.Create(ctx.Request.Context(), &entity); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "创建失败: " + err.Error()})
 		return
 	}
@@ -42,29 +56,53 @@ func (c Crud[T, CreateDTO]) Create(ctx *gin.Context) {
 }
 
 func (c Crud[T, CreateDTO]) Update(ctx *gin.Context) {
-	var entity T
 	var dto CreateDTO
 
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	key, err := keyFromPath(ctx, c.Keys)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的ID"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
 		return
 	}
 
-	if err := c.DB.First(&entity, id).Error; err != nil {
+	entity, err := c.Repo.Get(ctx.Request.Context(), key)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&dto); err != nil {
+	// 保留请求体，用于判断请求中出现了哪些字段
+	if err := ctx.ShouldBindBodyWith(&dto, binding.JSON); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
 		return
 	}
-	if err := copier.Copy(&entity, &dto); err != nil {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ctx.MustGet(gin.BodyBytesKey).([]byte), &fields); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
+		return
+	}
+	if err := copier.Copy(entity, &dto); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "数据映射失败: " + err.Error()})
 		return
 	}
-	if err := c.DB.Model(&entity).Updates(entity).Error; err != nil {
+	// 主键以路由为准，避免被请求体覆盖
+	for _, f := range c.Keys {
+		if err := f.Set(ctx.Request.Context(), reflect.ValueOf(entity).Elem(), key[f.DBName]); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "数据映射失败: " + err.Error()})
+			return
+		}
+	}
+	sch, err := c.Repo.Schema()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "更新失败: " + err.Error()})
+		return
+	}
+	// 只更新请求中出现的字段，false、0 和空字符串也会写入，未出现的字段保持不变
+	cols := columns[CreateDTO](sch, fields)
+	if len(cols) == 0 {
+		ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "更新成功", "data": entity})
+		return
+	}
+	if err := c.Repo.Update(ctx.Request.Context(), entity, cols...); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "更新失败: " + err.Error()})
 		return
 	}
@@ -74,13 +112,30 @@ func (c Crud[T, CreateDTO]) Update(ctx *gin.Context) {
 }
 
 func (c Crud[T, CreateDTO]) Delete(ctx *gin.Context) {
-	var entity T
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	key, err := keyFromPath(ctx, c.Keys)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+	c.delete(ctx, key)
+}
+
+// DeleteByKey 通过请求体中的主键对象删除，如 {"user_id":1,"role_id":2}
+func (c Crud[T, CreateDTO]) DeleteByKey(ctx *gin.Context) {
+	key, err := keyFromBody(ctx, c.Keys)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "无效的ID"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "参数错误: " + err.Error()})
 		return
 	}
-	if err := c.DB.Delete(&entity, id).Error; err != nil {
+	c.delete(ctx, key)
+}
+
+func (c Crud[T, CreateDTO]) delete(ctx *gin.Context, key repository.Key) {
+	if err := c.Repo.Delete(ctx.Request.Context(), key); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"code": 404, "message": "记录不存在"})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "删除失败: " + err.Error()})
 		return
 	}
@@ -88,20 +143,60 @@ func (c Crud[T, CreateDTO]) Delete(ctx *gin.Context) {
 
 }
 
+// Page 分页查询
+// 支持 ?page=1&limit=10&sort=-created_at,name&name__like=adm&status=1
 func (c Crud[T, CreateDTO]) Page(ctx *gin.Context) {
-	var entity T
-	var total int64
-	var list []T
 	// 分页参数
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 10
+	}
 
-	if err := c.DB.Model(&entity).Count(&total).Error; err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+	sch, err := c.Repo.Schema()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取列表失败: " + err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-	if err := c.DB.Model(&entity).Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+	opts := repository.ListOptions{Page: page, Limit: limit}
+	if sort := ctx.Query("sort"); sort != "" {
+		opts.Sort = strings.Split(sort, ",")
+		// 不允许按不对外输出的字段排序，排序结果同样会泄露字段的值
+		for _, s := range opts.Sort {
+			if field := strings.TrimPrefix(s, "-"); hidden(sch, field) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "不支持的排序字段: " + field})
+				return
+			}
+		}
+	}
+	for key, values := range ctx.Request.URL.Query() {
+		if reservedQuery[key] || len(values) == 0 {
+			continue
+		}
+		field, op, _ := strings.Cut(key, "__")
+		// 不允许按不对外输出的字段过滤，如密码
+		if hidden(sch, field) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": "不支持的过滤字段: " + field})
+			return
+		}
+		filter := repository.Filter{Field: field, Op: op, Value: values[0]}
+		if op == "in" {
+			filter.Value = strings.Split(values[0], ",")
+		}
+		opts.Filters = append(opts.Filters, filter)
+	}
+
+	// 列表查询读只读副本，ctx 已由 repository.Primary 标记时读主库
+	list, total, err := c.Repo.List(repository.Replica(ctx.Request.Context()), opts)
+	if errors.Is(err, repository.ErrInvalidQuery) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "获取列表失败: " + err.Error()})
 		return
 	}
@@ -117,3 +212,43 @@ func (c Crud[T, CreateDTO]) Page(ctx *gin.Context) {
 	})
 
 }
+
+// columns 请求中出现的 DTO 字段对应的列，按 json 标签匹配请求字段，按字段名匹配模型（与 copier 一致）
+// 主键和创建时间不更新，嵌入的结构体展开
+func columns[DTO any](sch *schema.Schema, present map[string]json.RawMessage) []string {
+	var cols []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct && name == "" {
+				walk(sf.Type)
+				continue
+			}
+			if !sf.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			if _, ok := present[name]; !ok {
+				continue
+			}
+			f := sch.LookUpField(sf.Name)
+			if f != nil && f.DBName != "" && f.Updatable && !f.PrimaryKey && f.AutoCreateTime == 0 {
+				cols = append(cols, f.DBName)
+			}
+		}
+	}
+	if t := reflect.TypeOf((*DTO)(nil)).Elem(); t.Kind() == reflect.Struct {
+		walk(t)
+	}
+	return cols
+}
+
+// hidden 不对外输出的字段（json:"-"），如密码和两步验证密钥
+func hidden(sch *schema.Schema, name string) bool {
+	f := sch.LookUpField(name)
+	return f != nil && f.Tag.Get("json") == "-"
+}
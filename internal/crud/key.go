@@ -0,0 +1,115 @@
+package crud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"tier-up/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/schema"
+)
+
+// KeyPath 主键路由片段
+// 单一主键固定为 /:id，复合主键按列名依次拼接，如 /:user_id/:role_id
+func KeyPath(fields []*schema.Field) string {
+	if len(fields) == 1 {
+		return "/:id"
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString("/:" + f.DBName)
+	}
+	return b.String()
+}
+
+// keyParam 主键字段对应的路由参数名
+func keyParam(fields []*schema.Field, f *schema.Field) string {
+	if len(fields) == 1 {
+		return "id"
+	}
+	return f.DBName
+}
+
+// keyFromPath 从路由参数解析主键
+func keyFromPath(ctx *gin.Context, fields []*schema.Field) (repository.Key, error) {
+	key := repository.Key{}
+	for _, f := range fields {
+		v, err := parseKeyValue(f, ctx.Param(keyParam(fields, f)))
+		if err != nil {
+			return nil, err
+		}
+		key[f.DBName] = v
+	}
+	return key, nil
+}
+
+// keyFromBody 从请求体的主键对象解析主键
+// 字段名可使用列名、json名或结构体字段名
+func keyFromBody(ctx *gin.Context, fields []*schema.Field) (repository.Key, error) {
+	// 数字保留为 json.Number，避免 float64 丢失大整数的精度或格式化为科学计数法
+	var body map[string]interface{}
+	dec := json.NewDecoder(ctx.Request.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return nil, err
+	}
+	key := repository.Key{}
+	for _, f := range fields {
+		raw, ok := lookupKey(body, f)
+		if !ok {
+			return nil, fmt.Errorf("缺少主键字段 %s", f.DBName)
+		}
+		v, err := parseKeyValue(f, fmt.Sprint(raw))
+		if err != nil {
+			return nil, err
+		}
+		key[f.DBName] = v
+	}
+	return key, nil
+}
+
+func lookupKey(body map[string]interface{}, f *schema.Field) (interface{}, bool) {
+	names := []string{f.DBName, f.Name}
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		names = append([]string{name}, names...)
+	}
+	for _, name := range names {
+		if v, ok := body[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// parseKeyValue 按字段类型转换主键值
+func parseKeyValue(f *schema.Field, raw string) (interface{}, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("缺少主键 %s", f.DBName)
+	}
+	t := f.FieldType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的主键 %s", f.DBName)
+		}
+		return v, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的主键 %s", f.DBName)
+		}
+		return v, nil
+	case reflect.String:
+		return raw, nil
+	}
+	return nil, errors.New("不支持的主键类型: " + t.String())
+}
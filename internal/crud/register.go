@@ -1,30 +1,42 @@
 package crud
 
 import (
-	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	"reflect"
+
+	"tier-up/internal/repository"
+	"tier-up/internal/route"
 )
 
 // 根据配置 创建
 func RegisterCrudRoutes[T any, C any](
-	r *gin.RouterGroup,
-	db *gorm.DB,
+	r *route.Group,
+	repo *repository.Repository[T],
 ) {
 	// 解析model tag配置
 	config := ParseModelConfig[T]()
-	var handle ICrud[T] = Crud[T, C]{DB: db}
-	group := r.Group(config.Prefix)
+	// 通过 GORM 解析主键，支持非 Base 模型和复合主键
+	keys, err := repo.PrimaryFields()
+	if err != nil {
+		panic(err)
+	}
+	var handle ICrud[T] = Crud[T, C]{Repo: repo, Keys: keys}
+	name := reflect.TypeOf((*T)(nil)).Elem().Name()
+	group := r.Group(config.Prefix).With(route.Meta{Model: name, Tag: name})
+	keyPath := KeyPath(keys)
 	// 按需注册路由
 	if config.Create {
-		group.POST("/create", handle.Create)
+		group.With(route.Meta{Action: "create"}).POST("/create", handle.Create)
 	}
 	if config.Update {
-		group.PUT("/update/:id", handle.Update)
+		group.With(route.Meta{Action: "update"}).PUT("/update"+keyPath, handle.Update)
 	}
 	if config.Delete {
-		group.DELETE("/delete/:id", handle.Delete)
+		group.With(route.Meta{Action: "delete"}).DELETE("/delete"+keyPath, handle.Delete)
+		if len(keys) > 1 {
+			group.With(route.Meta{Action: "delete"}).DELETE("/delete", handle.DeleteByKey)
+		}
 	}
 	if config.Page {
-		group.GET("/page", handle.Page)
+		group.With(route.Meta{Action: "page"}).GET("/page", handle.Page)
 	}
 }
@@ -0,0 +1,126 @@
+package crud
+
+import (
+	"reflect"
+	"sync"
+
+	"tier-up/internal/repository"
+	"tier-up/internal/route"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Access 路由访问级别
+type Access int
+
+const (
+	AccessRBAC   Access = iota // 需要登录和权限验证，默认
+	AccessAuth                 // 只需要登录
+	AccessPublic               // 不需要登录
+)
+
+// Options 模型注册选项
+type Options struct {
+	Access Access // crud 路由的访问级别
+}
+
+// Model 已注册的模型
+type Model struct {
+	Name    string
+	Type    reflect.Type // 模型类型
+	DTO     reflect.Type // 创建、更新使用的请求类型，RegisterTable 注册的模型为空
+	Config  RouteConfig  // crud 标签配置
+	Options Options
+
+	mount func(g *route.Group, db *gorm.DB)
+}
+
+// HasRoutes 是否生成 crud 路由，RegisterTable 注册的模型没有路由
+func (m *Model) HasRoutes() bool {
+	return m.mount != nil && (m.Config.Create || m.Config.Update || m.Config.Delete || m.Config.Page)
+}
+
+// New 返回模型的新实例指针，用于迁移
+func (m *Model) New() interface{} {
+	return reflect.New(m.Type).Interface()
+}
+
+// Schema 解析模型结构，使用默认命名策略
+func (m *Model) Schema() (*schema.Schema, error) {
+	return schema.Parse(m.New(), &schemaCache, schema.NamingStrategy{})
+}
+
+var (
+	modelsMu    sync.RWMutex
+	models      []*Model
+	schemaCache sync.Map
+)
+
+// Register 注册模型，迁移、路由和文档生成都从注册表读取
+// 注册顺序即迁移顺序，关联表需在被关联的表之后注册
+func Register[T any, C any](opts Options) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	add(&Model{
+		Name:    t.Name(),
+		Type:    t,
+		DTO:     reflect.TypeOf((*C)(nil)).Elem(),
+		Config:  ParseModelConfig[T](),
+		Options: opts,
+		mount: func(g *route.Group, db *gorm.DB) {
+			RegisterCrudRoutes[T, C](g, repository.NewRepository[T](db))
+		},
+	})
+}
+
+// RegisterTable 注册只用于迁移和查询代码生成的模型，不生成 crud 路由，crud 标签也不生效
+// 用于令牌、会话等内部表，避免误加标签后对外暴露
+func RegisterTable[T any]() {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	add(&Model{Name: t.Name(), Type: t})
+}
+
+func add(m *Model) {
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+
+	for _, existing := range models {
+		if existing.Type == m.Type {
+			panic("模型重复注册: " + m.Type.String())
+		}
+	}
+	models = append(models, m)
+}
+
+// Models 返回已注册的模型，按注册顺序
+func Models() []*Model {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	list := make([]*Model, len(models))
+	copy(list, models)
+	return list
+}
+
+// Groups 各访问级别对应的路由组
+type Groups struct {
+	Public *route.Group
+	Auth   *route.Group
+	RBAC   *route.Group
+}
+
+// Mount 为已注册的模型注册 crud 路由
+func Mount(groups Groups, db *gorm.DB) {
+	for _, m := range Models() {
+		if !m.HasRoutes() {
+			continue
+		}
+		switch m.Options.Access {
+		case AccessPublic:
+			m.mount(groups.Public, db)
+		case AccessAuth:
+			m.mount(groups.Auth, db)
+		default:
+			m.mount(groups.RBAC, db)
+		}
+	}
+}
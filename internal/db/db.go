@@ -1,18 +1,46 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"tier-up/internal/config"
+	"tier-up/internal/db/driver"
 	"time"
 
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Open 按 DB.Driver 连接数据库，设置连接池、SQL 日志和只读副本，不迁移表
+func Open(c config.Config, opts ...gorm.Option) (*gorm.DB, error) {
+	dialector, err := driver.Dialector(c.DB)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		log, err := NewLogger(c.DB.Log)
+		if err != nil {
+			return nil, err
+		}
+		opts = []gorm.Option{&gorm.Config{Logger: log}}
+	}
+	db, err := gorm.Open(dialector, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sqldb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	configurePool(sqldb, c.DB.Pool)
+	if err := useReplicas(db, c.DB); err != nil {
+		return nil, fmt.Errorf("配置只读副本失败: %w", err)
+	}
+	return db, nil
+}
+
 func InitDB(c config.Config) (*sql.DB, *gorm.DB) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai", c.DB.Host, c.DB.User, c.DB.Password, c.DB.DriverName, c.DB.Port)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := Open(c)
 
 	if err != nil {
 		fmt.Print("\n", "db", db)
@@ -21,7 +49,13 @@ func InitDB(c config.Config) (*sql.DB, *gorm.DB) {
 		fmt.Print("\n", "链接数据库失败")
 		panic(err)
 	}
-	// 迁移表
+	// 版本化迁移，多实例启动时通过数据库锁保证只执行一次
+	if c.DB.MigrateOnStart {
+		if _, err := Migrate(context.Background(), db); err != nil {
+			panic(err)
+		}
+	}
+	// 按模型自动建表，仅用于开发环境
 	if c.DB.AutoCreateTable {
 		AutoMigrate(db)
 	}
@@ -32,18 +66,33 @@ func InitDB(c config.Config) (*sql.DB, *gorm.DB) {
 		panic("连接池启动出错")
 	}
 
-	// SetMaxIdleConns 设置空闲连接池中连接的最大数量
-	sqldb.SetMaxIdleConns(10)
-
-	// SetMaxOpenConns 设置打开数据库连接的最大数量。
-	sqldb.SetMaxOpenConns(100)
-
-	// SetConnMaxLifetime 设置了连接可复用的最大时间。
-	sqldb.SetConnMaxLifetime(time.Hour)
-
 	if err = sqldb.Ping(); err != nil {
 		panic(err)
 	}
 
 	return sqldb, db
 }
+
+// pool *sql.DB 的连接池设置，主库和副本共用
+type pool interface {
+	SetMaxIdleConns(n int)
+	SetMaxOpenConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+	SetConnMaxIdleTime(d time.Duration)
+}
+
+// configurePool 设置连接池，为 0 的项不设置
+func configurePool(p pool, c config.DbPoolConfig) {
+	if c.MaxIdleConns > 0 {
+		p.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.MaxOpenConns > 0 {
+		p.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		p.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+	if c.ConnMaxIdleTime > 0 {
+		p.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	}
+}
@@ -0,0 +1,152 @@
+// Package driver 数据库驱动，根据配置选择 gorm Dialector 和 DSN 格式
+// 驱动相关的 SQL 差异（如不区分大小写的 LIKE）也在这里处理
+package driver
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"tier-up/internal/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// Driver 数据库驱动
+type Driver struct {
+	Name    string   // gorm Dialector 名称
+	Aliases []string // 配置中可用的其他名称
+	DSN     func(c config.DbConfig) string
+	Open    func(dsn string) gorm.Dialector
+}
+
+// Memory SQLite 内存数据库的库名
+const Memory = ":memory:"
+
+var drivers = []*Driver{
+	{
+		Name:    "postgres",
+		Aliases: []string{"postgresql", "pgsql", "pg"},
+		DSN: func(c config.DbConfig) string {
+			dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", c.Host, c.User, c.Password, c.DriverName, c.Port)
+			if c.TimeZone != "" {
+				dsn += " TimeZone=" + c.TimeZone
+			}
+			return dsn
+		},
+		Open: postgres.Open,
+	},
+	{
+		Name: "mysql",
+		DSN: func(c config.DbConfig) string {
+			charset := c.Charset
+			if charset == "" {
+				charset = "utf8mb4"
+			}
+			params := url.Values{}
+			params.Set("charset", charset)
+			params.Set("parseTime", "True")
+			if c.TimeZone != "" {
+				params.Set("loc", c.TimeZone)
+			}
+			return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?%s", c.User, c.Password, c.Host, c.Port, c.DriverName, params.Encode())
+		},
+		Open: mysql.Open,
+	},
+	{
+		Name:    "sqlserver",
+		Aliases: []string{"mssql"},
+		// SQL Server 连接没有时区参数，时间按原值存取
+		DSN: func(c config.DbConfig) string {
+			u := url.URL{
+				Scheme:   "sqlserver",
+				User:     url.UserPassword(c.User, c.Password),
+				Host:     c.Host + ":" + c.Port,
+				RawQuery: url.Values{"database": {c.DriverName}}.Encode(),
+			}
+			return u.String()
+		},
+		Open: sqlserver.Open,
+	},
+	{
+		Name:    "sqlite",
+		Aliases: []string{"sqlite3"},
+		// DriverName 为数据库文件路径，:memory: 为内存数据库，用于本地开发和测试
+		// 内存库使用共享缓存，连接池中的连接访问同一个库
+		DSN: func(c config.DbConfig) string {
+			if c.DriverName == "" || c.DriverName == Memory {
+				return "file::memory:?cache=shared&_pragma=foreign_keys(1)"
+			}
+			return c.DriverName + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+		},
+		Open: sqlite.Open,
+	},
+}
+
+// Lookup 按名称或别名查找驱动，为空时为 postgres
+func Lookup(name string) (*Driver, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = "postgres"
+	}
+	for _, d := range drivers {
+		if d.Name == name {
+			return d, nil
+		}
+		for _, alias := range d.Aliases {
+			if alias == name {
+				return d, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("不支持的数据库驱动 %s，可选 %s", name, strings.Join(Names(), "、"))
+}
+
+// Names 支持的驱动名称
+func Names() []string {
+	names := make([]string, 0, len(drivers))
+	for _, d := range drivers {
+		names = append(names, d.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DSN 配置对应的连接字符串，配置了 DB.DSN 时直接使用
+func DSN(c config.DbConfig) (string, error) {
+	d, err := Lookup(c.Driver)
+	if err != nil {
+		return "", err
+	}
+	if c.DSN != "" {
+		return c.DSN, nil
+	}
+	return d.DSN(c), nil
+}
+
+// Dialector 配置对应的 gorm Dialector
+func Dialector(c config.DbConfig) (gorm.Dialector, error) {
+	d, err := Lookup(c.Driver)
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := DSN(c)
+	if err != nil {
+		return nil, err
+	}
+	return d.Open(dsn), nil
+}
+
+// ILike 不区分大小写的模糊匹配条件，参数为 %值%
+// Postgres 使用 ILIKE，MySQL、SQL Server 的默认排序规则和 SQLite 的 LIKE 本身不区分大小写（SQLite 仅限 ASCII）
+func ILike(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "postgres" {
+		return column + " ILIKE ?"
+	}
+	return column + " LIKE ?"
+}
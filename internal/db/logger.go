@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"tier-up/internal/app/middleware/requestid"
+	"tier-up/internal/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// Logger 结构化 SQL 日志，记录耗时、影响行数和请求 ID
+// 超过 SlowThreshold 的查询以 warn 级别记录并标记 slow
+type Logger struct {
+	Log           *slog.Logger
+	Level         logger.LogLevel
+	SlowThreshold time.Duration
+	HideParams    bool // 日志中的 SQL 不包含参数值
+}
+
+// NewLogger 根据配置创建 SQL 日志
+func NewLogger(c config.DbLogConfig) (*Logger, error) {
+	level, err := logLevel(c.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{
+		Log:           slog.Default().With("component", "gorm"),
+		Level:         level,
+		SlowThreshold: c.SlowThreshold,
+		HideParams:    c.HideParams,
+	}, nil
+}
+
+func logLevel(name string) (logger.LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "silent":
+		return logger.Silent, nil
+	case "error":
+		return logger.Error, nil
+	case "", "warn":
+		return logger.Warn, nil
+	case "info":
+		return logger.Info, nil
+	}
+	return 0, fmt.Errorf("无效的 SQL 日志级别 %s，可选 silent、error、warn、info", name)
+}
+
+// LogMode 实现 logger.Interface
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	n := *l
+	n.Level = level
+	return &n
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.Level >= logger.Info {
+		l.Log.LogAttrs(ctx, slog.LevelInfo, fmt.Sprintf(msg, args...), l.attrs(ctx)...)
+	}
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.Level >= logger.Warn {
+		l.Log.LogAttrs(ctx, slog.LevelWarn, fmt.Sprintf(msg, args...), l.attrs(ctx)...)
+	}
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.Level >= logger.Error {
+		l.Log.LogAttrs(ctx, slog.LevelError, fmt.Sprintf(msg, args...), l.attrs(ctx)...)
+	}
+}
+
+// Trace 记录一条 SQL，错误和慢查询在 error、warn 级别记录，其他在 info 级别记录
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.Level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	slow := l.SlowThreshold > 0 && elapsed > l.SlowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+
+	var level slog.Level
+	switch {
+	case failed && l.Level >= logger.Error:
+		level = slog.LevelError
+	case slow && l.Level >= logger.Warn:
+		level = slog.LevelWarn
+	case l.Level >= logger.Info:
+		level = slog.LevelInfo
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	attrs := append(l.attrs(ctx),
+		slog.String("sql", sql),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		slog.Int64("rows", rows),
+		slog.String("source", utils.FileWithLineNum()),
+	)
+	if slow {
+		attrs = append(attrs, slog.Bool("slow", true), slog.String("threshold", l.SlowThreshold.String()))
+	}
+	if failed {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.Log.LogAttrs(ctx, level, "sql", attrs...)
+}
+
+// ParamsFilter 实现 gorm 的参数过滤，HideParams 时日志中只保留占位符
+func (l *Logger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.HideParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
+func (l *Logger) attrs(ctx context.Context) []slog.Attr {
+	if id := requestid.FromContext(ctx); id != "" {
+		return []slog.Attr{slog.String("request_id", id)}
+	}
+	return nil
+}
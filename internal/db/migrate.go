@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	_ "tier-up/internal/db/migrations" // 注册迁移
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行全部未执行的版本化迁移
+func Migrate(ctx context.Context, db *gorm.DB) ([]migrate.Migration, error) {
+	m, err := migrate.NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	done, err := m.Up(ctx, 0)
+	for _, mg := range done {
+		fmt.Printf("迁移 %d_%s 完成\n", mg.Version, mg.Name)
+	}
+	return done, err
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 基线迁移，创建系统表
+// 结构为当时模型的快照，之后修改模型不影响本迁移，已存在的表只补充缺少的列和索引
+func init() {
+	migrate.Register(migrate.Migration{
+		Version: 20261019000000,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			type Base struct {
+				ID        uint64 `gorm:"primarykey"`
+				CreatedAt time.Time
+				UpdatedAt time.Time
+				DeletedAt gorm.DeletedAt `gorm:"index"`
+			}
+			type Role struct {
+				Base
+				Name        string `gorm:"size:50;not null;unique"`
+				DisplayName string `gorm:"size:100"`
+				Description string `gorm:"size:200"`
+			}
+			type User struct {
+				Base
+				Username string `gorm:"size:50;not null;unique"`
+				Password string `gorm:"size:100;not null"`
+				Nickname string `gorm:"size:50"`
+				Email    string `gorm:"size:100;unique"`
+				Phone    string `gorm:"size:20"`
+				Avatar   string `gorm:"size:255"`
+				Status   int    `gorm:"default:1"`
+				Roles    []Role `gorm:"many2many:user_roles;"`
+			}
+			type Menu struct {
+				Base
+				Code      string
+				Name      string `gorm:"not null;"`
+				Path      string `gorm:"not null;comment:api路径;"`
+				Component string `gorm:"not null;comment:组件路径;"`
+				Icon      string `gorm:"comment:icon图标;"`
+				Note      string `gorm:"comment:备注;"`
+				Type      int
+				Status    *int    `gorm:"comment:状态:1正常 2禁用;"`
+				Sort      int     `gorm:"comment:显示顺序;"`
+				ParentId  *uint64 `gorm:"column:parent_id"`
+				Children  []Menu  `gorm:"foreignKey:ParentId"`
+			}
+			type IdempotencyKey struct {
+				Key         string `gorm:"column:idempotency_key;size:255;primaryKey"`
+				Fingerprint string `gorm:"size:64;not null"`
+				Status      string `gorm:"size:20;not null"`
+				StatusCode  int
+				ContentType string `gorm:"size:100"`
+				Body        []byte
+				ExpiresAt   time.Time `gorm:"index;not null"`
+				CreatedAt   time.Time
+			}
+			return tx.Migrator().AutoMigrate(&User{}, &Role{}, &Menu{}, &IdempotencyKey{})
+		},
+		Down: func(tx *gorm.DB) error {
+			// DropTable 按参数倒序删除，被引用的表放在前面
+			return tx.Migrator().DropTable("idempotency_keys", "menus", "roles", "users", "user_roles")
+		},
+	})
+}
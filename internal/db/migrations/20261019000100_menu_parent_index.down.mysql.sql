@@ -0,0 +1 @@
+DROP INDEX idx_menus_parent_id ON menus;
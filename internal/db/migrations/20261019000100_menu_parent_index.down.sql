@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_menus_parent_id;
@@ -0,0 +1,2 @@
+-- 菜单树按 parent_id 查询子节点
+CREATE INDEX IF NOT EXISTS idx_menus_parent_id ON menus (parent_id);
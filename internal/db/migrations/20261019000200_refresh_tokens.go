@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 刷新令牌表
+func init() {
+	migrate.Register(migrate.Migration{
+		Version: 20261019000200,
+		Name:    "refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			type RefreshToken struct {
+				ID        uint64    `gorm:"primarykey"`
+				UserID    uint64    `gorm:"index;not null"`
+				FamilyID  string    `gorm:"size:32;index;not null"`
+				TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+				ExpiresAt time.Time `gorm:"index;not null"`
+				UsedAt    *time.Time
+				RevokedAt *time.Time
+				CreatedAt time.Time
+			}
+			return tx.Migrator().AutoMigrate(&RefreshToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("refresh_tokens")
+		},
+	})
+}
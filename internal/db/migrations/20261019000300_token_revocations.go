@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 访问令牌吊销记录
+func init() {
+	migrate.Register(migrate.Migration{
+		Version: 20261019000300,
+		Name:    "token_revocations",
+		Up: func(tx *gorm.DB) error {
+			type TokenRevocation struct {
+				ID        uint64    `gorm:"primarykey"`
+				JTI       string    `gorm:"column:jti;size:64;index"`
+				UserID    uint64    `gorm:"index;not null"`
+				RevokedAt time.Time `gorm:"not null"`
+				ExpiresAt time.Time `gorm:"index;not null"`
+			}
+			return tx.Migrator().AutoMigrate(&TokenRevocation{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("token_revocations")
+		},
+	})
+}
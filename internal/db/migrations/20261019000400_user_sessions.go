@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 登录会话，吊销记录增加会话
+func init() {
+	type UserSession struct {
+		ID         uint64 `gorm:"primarykey"`
+		SessionID  string `gorm:"size:32;uniqueIndex;not null"`
+		UserID     uint64 `gorm:"index;not null"`
+		UserAgent  string `gorm:"size:500"`
+		IP         string `gorm:"column:ip;size:64"`
+		CreatedAt  time.Time
+		LastSeenAt time.Time
+		ExpiresAt  time.Time `gorm:"index;not null"`
+		RevokedAt  *time.Time
+	}
+	// 只声明新增的列，已有的表只补充该列和索引
+	type TokenRevocation struct {
+		SessionID string `gorm:"size:32;index"`
+	}
+
+	migrate.Register(migrate.Migration{
+		Version: 20261019000400,
+		Name:    "user_sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&UserSession{}, &TokenRevocation{})
+		},
+		Down: func(tx *gorm.DB) error {
+			m := tx.Migrator()
+			if err := m.DropIndex(&TokenRevocation{}, "SessionID"); err != nil {
+				return err
+			}
+			if err := m.DropColumn(&TokenRevocation{}, "SessionID"); err != nil {
+				return err
+			}
+			return m.DropTable(&UserSession{})
+		},
+	})
+}
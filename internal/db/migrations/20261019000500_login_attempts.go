@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 登录失败计数
+func init() {
+	migrate.Register(migrate.Migration{
+		Version: 20261019000500,
+		Name:    "login_attempts",
+		Up: func(tx *gorm.DB) error {
+			type LoginAttempt struct {
+				ID           uint64    `gorm:"primarykey"`
+				Key          string    `gorm:"column:attempt_key;size:255;uniqueIndex;not null"`
+				Failures     int       `gorm:"not null;default:0"`
+				LastFailedAt time.Time `gorm:"index"`
+				BlockedUntil *time.Time
+				LockedAt     *time.Time
+			}
+			return tx.Migrator().AutoMigrate(&LoginAttempt{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("login_attempts")
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 密码历史，用户增加密码过期时间，已有用户为空，修改密码后开始计算
+func init() {
+	type PasswordHistory struct {
+		ID        uint64 `gorm:"primarykey"`
+		UserID    uint64 `gorm:"index;not null"`
+		Hash      string `gorm:"size:100;not null"`
+		CreatedAt time.Time
+	}
+	// 只声明新增的列
+	type User struct {
+		PasswordExpiresAt *time.Time
+	}
+
+	migrate.Register(migrate.Migration{
+		Version: 20261019000600,
+		Name:    "password_policy",
+		// SQLite 回滚删除列时重建 users 表，需要关闭外键检查，事务由 WithoutForeignKeys 开启
+		NoTransaction: true,
+		Up: func(db *gorm.DB) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AutoMigrate(&PasswordHistory{}, &User{})
+			})
+		},
+		Down: func(db *gorm.DB) error {
+			return migrate.WithoutForeignKeys(db, func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&User{}, "PasswordExpiresAt"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable("password_histories")
+			})
+		},
+	})
+}
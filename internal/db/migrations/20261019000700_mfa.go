@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"time"
+
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+// 两步验证：用户的 TOTP 密钥，角色是否要求两步验证，恢复码和登录验证记录
+func init() {
+	// 只声明新增的列
+	type User struct {
+		TOTPSecret    string     `gorm:"column:totp_secret;size:64"`
+		TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at"`
+		TOTPLastStep  int64      `gorm:"column:totp_last_step;not null;default:0"`
+	}
+	type Role struct {
+		RequireMFA bool `gorm:"column:require_mfa;not null;default:false"`
+	}
+	type RecoveryCode struct {
+		ID        uint64 `gorm:"primarykey"`
+		UserID    uint64 `gorm:"index;not null"`
+		CodeHash  string `gorm:"size:64;not null"`
+		UsedAt    *time.Time
+		CreatedAt time.Time
+	}
+	type MFAChallenge struct {
+		ID        uint64    `gorm:"primarykey"`
+		UserID    uint64    `gorm:"index;not null"`
+		TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+		Attempts  int       `gorm:"not null;default:0"`
+		ExpiresAt time.Time `gorm:"index;not null"`
+		UsedAt    *time.Time
+		CreatedAt time.Time
+	}
+
+	migrate.Register(migrate.Migration{
+		Version: 20261019000700,
+		Name:    "mfa",
+		// SQLite 回滚删除列时重建 users 表，需要关闭外键检查，事务由 WithoutForeignKeys 开启
+		NoTransaction: true,
+		Up: func(db *gorm.DB) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AutoMigrate(&User{}, &Role{}, &RecoveryCode{}, &MFAChallenge{})
+			})
+		},
+		Down: func(db *gorm.DB) error {
+			return migrate.WithoutForeignKeys(db, func(tx *gorm.DB) error {
+				m := tx.Migrator()
+				for _, column := range []string{"TOTPSecret", "TOTPEnabledAt", "TOTPLastStep"} {
+					if err := m.DropColumn(&User{}, column); err != nil {
+						return err
+					}
+				}
+				if err := m.DropColumn(&Role{}, "RequireMFA"); err != nil {
+					return err
+				}
+				return m.DropTable("recovery_codes", "mfa_challenges")
+			})
+		},
+	})
+}
@@ -0,0 +1,19 @@
+// Package migrations 版本化数据库迁移
+// Go 迁移在 init 中调用 migrate.Register 注册，SQL 迁移放在本目录并随程序嵌入
+// 语法不通用的 SQL 使用 <版本>_<名称>.up.<数据库>.sql 为对应数据库单独编写
+// 新建迁移: go run ./cmd/tier migrate create add_user_phone [--go]
+// 已执行的迁移不要修改，结构变化通过新迁移完成
+package migrations
+
+import (
+	"embed"
+
+	"tier-up/internal/migrate"
+)
+
+//go:embed *.sql
+var sqlFiles embed.FS
+
+func init() {
+	migrate.RegisterFS(sqlFiles)
+}
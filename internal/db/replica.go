@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+
+	"tier-up/internal/config"
+	"tier-up/internal/db/driver"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// useReplicas 按配置注册 dbresolver 读写分离
+// 写操作和事务使用主库，查询默认读主库，repository.Replica 标记的查询读副本
+func useReplicas(db *gorm.DB, c config.DbConfig) error {
+	if len(c.Replicas) == 0 && len(c.Resolvers) == 0 {
+		return nil
+	}
+	resolver := &dbresolver.DBResolver{}
+	if len(c.Replicas) > 0 {
+		replicas, err := dialectors(c, c.Replicas)
+		if err != nil {
+			return err
+		}
+		policy, err := replicaPolicy(c.ReplicaPolicy)
+		if err != nil {
+			return err
+		}
+		resolver.Register(dbresolver.Config{Replicas: replicas, Policy: policy})
+	}
+	for i, r := range c.Resolvers {
+		if len(r.Tables) == 0 {
+			return fmt.Errorf("DB.Resolvers[%d] 缺少 Tables", i)
+		}
+		sources, err := dialectors(c, r.Sources)
+		if err != nil {
+			return err
+		}
+		replicas, err := dialectors(c, r.Replicas)
+		if err != nil {
+			return err
+		}
+		name := r.Policy
+		if name == "" {
+			name = c.ReplicaPolicy
+		}
+		policy, err := replicaPolicy(name)
+		if err != nil {
+			return err
+		}
+		tables := make([]interface{}, 0, len(r.Tables))
+		for _, t := range r.Tables {
+			tables = append(tables, t)
+		}
+		resolver.Register(dbresolver.Config{Sources: sources, Replicas: replicas, Policy: policy}, tables...)
+	}
+	// 副本连接池与主库相同
+	resolver.Call(func(conn gorm.ConnPool) error {
+		if p, ok := conn.(pool); ok {
+			configurePool(p, c.Pool)
+		}
+		return nil
+	})
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+	return repository.ReadPrimaryByDefault(db)
+}
+
+// dialectors 副本的 Dialector，未填写的连接参数使用主库配置
+func dialectors(c config.DbConfig, nodes []config.DbNode) ([]gorm.Dialector, error) {
+	var list []gorm.Dialector
+	for _, n := range nodes {
+		nc := c
+		nc.DSN = n.DSN
+		for dst, src := range map[*string]string{&nc.Host: n.Host, &nc.Port: n.Port, &nc.User: n.User, &nc.Password: n.Password, &nc.DriverName: n.DriverName} {
+			if src != "" {
+				*dst = src
+			}
+		}
+		d, err := driver.Dialector(nc)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+func replicaPolicy(name string) (dbresolver.Policy, error) {
+	switch name {
+	case "", "random":
+		return dbresolver.RandomPolicy{}, nil
+	case "round_robin", "round-robin":
+		return dbresolver.RoundRobinPolicy(), nil
+	}
+	return nil, fmt.Errorf("不支持的副本策略 %s，可选 random、round_robin", name)
+}
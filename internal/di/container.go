@@ -1,20 +1,103 @@
 package di
 
 import (
+	"log/slog"
+	"reflect"
+
 	"tier-up/api/v1/controller"
+	"tier-up/internal/app/middleware/cors"
+	"tier-up/internal/app/middleware/idempotency"
 	"tier-up/internal/app/middleware/jwt"
+	"tier-up/internal/app/middleware/ratelimit"
+	"tier-up/internal/app/model"
+	"tier-up/internal/app/query"
 	"tier-up/internal/app/service"
+	"tier-up/internal/config"
+	"tier-up/internal/repository"
+	"tier-up/internal/route"
 
 	"go.uber.org/dig"
 	"gorm.io/gorm"
 )
 
-func BuildContainer(db *gorm.DB) *dig.Container {
+// BuildContainer 注册所有依赖，config.Config 为创建容器时的配置
+// 需要热更新的组件订阅 Manager 的配置变化
+func BuildContainer(m *config.Manager, db *gorm.DB) *dig.Container {
 	container := dig.New()
 
 	// 基础服务
+	container.Provide(func() *config.Manager { return m })
+	container.Provide(m.Get)
 	container.Provide(func() *gorm.DB { return db })
-	container.Provide(jwt.NewJWTService)
+	// 有效期和非对称密钥修改后立即生效，HS256 密钥等修改需要重启
+	// 在构造时订阅，创建失败的错误由 Invoke 返回
+	container.Provide(func(cfg config.Config, m *config.Manager, db *gorm.DB) (*jwt.JWTService, error) {
+		s, err := jwt.NewJWTService(cfg, db)
+		if err != nil {
+			return nil, err
+		}
+		m.OnChange(func(old, cur config.Config) {
+			if cur.JWT.AccessTTL != old.JWT.AccessTTL || cur.JWT.RefreshTTL != old.JWT.RefreshTTL {
+				s.SetTTL(cur.JWT.AccessTTL, cur.JWT.RefreshTTL)
+			}
+			if !reflect.DeepEqual(cur.JWT.Keys, old.JWT.Keys) {
+				if err := s.SetKeys(cur.JWT.Keys); err != nil {
+					slog.Error("读取 JWT 密钥失败，继续使用当前密钥", "error", err)
+				}
+			}
+		})
+		return s, nil
+	})
+	container.Provide(func(m *config.Manager) *cors.CORS {
+		c := cors.New(m.Get().CORS)
+		m.OnChange(func(_, cur config.Config) { c.Update(cur.CORS) })
+		return c
+	})
+	container.Provide(func(m *config.Manager) *ratelimit.Limiter {
+		l := ratelimit.New(m.Get().RateLimit)
+		m.OnChange(func(old, cur config.Config) {
+			if cur.RateLimit != old.RateLimit {
+				l.Update(cur.RateLimit)
+			}
+		})
+		return l
+	})
+	container.Provide(func(m *config.Manager, db *gorm.DB) *service.LoginGuard {
+		g := service.NewLoginGuard(m.Get(), db)
+		m.OnChange(func(old, cur config.Config) {
+			if cur.Login != old.Login {
+				g.Update(cur.Login)
+			}
+		})
+		return g
+	})
+	container.Provide(func(m *config.Manager, db *gorm.DB) *service.PasswordPolicy {
+		p := service.NewPasswordPolicy(m.Get(), db)
+		m.OnChange(func(old, cur config.Config) {
+			if cur.Password != old.Password {
+				p.Update(cur.Password)
+			}
+		})
+		return p
+	})
+	container.Provide(func(m *config.Manager, db *gorm.DB, jwtService *jwt.JWTService, guard *service.LoginGuard) *service.MFAService {
+		s := service.NewMFAService(m.Get(), db, jwtService, guard)
+		m.OnChange(func(old, cur config.Config) {
+			if cur.MFA != old.MFA {
+				s.Update(cur.MFA)
+			}
+		})
+		return s
+	})
+	container.Provide(idempotency.NewIdempotency)
+	container.Provide(route.NewRegistry)
+
+	// 数据访问
+	container.Provide(repository.NewRepository[model.User])
+	container.Provide(repository.NewRepository[model.Role])
+	container.Provide(repository.NewRepository[model.Menu])
+	// gorm/gen 生成的类型安全查询，由 go run ./cmd/tier gen query 生成
+	container.Provide(func(db *gorm.DB) *query.Query { return query.Use(db) })
 
 	// 业务服务
 	container.Provide(service.NewUserService)
@@ -24,6 +107,8 @@ func BuildContainer(db *gorm.DB) *dig.Container {
 	container.Provide(controller.NewUserController)
 	container.Provide(controller.NewRoleController)
 	container.Provide(controller.NewMenuController)
+	container.Provide(controller.NewSystemController)
+	container.Provide(controller.NewMFAController)
 
 	return container
 }
@@ -0,0 +1,42 @@
+// Package logging 应用日志，基于 log/slog
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"tier-up/internal/config"
+)
+
+// Level 全局日志级别，修改后立即生效
+var Level = new(slog.LevelVar)
+
+// Setup 按配置设置 slog 默认日志
+func Setup(c config.LoggingConfig) error {
+	level, err := ParseLevel(c.Level)
+	if err != nil {
+		return err
+	}
+	Level.Set(level)
+
+	opts := &slog.HandlerOptions{Level: Level}
+	var handler slog.Handler
+	if c.Format == "text" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// ParseLevel 解析 debug、info、warn、error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(name))); err != nil {
+		return 0, fmt.Errorf("无效的日志级别 %s，可选 debug、info、warn、error", name)
+	}
+	return level, nil
+}
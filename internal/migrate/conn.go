@@ -0,0 +1,35 @@
+package migrate
+
+import (
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// openConn 迁移使用的连接，返回关闭函数
+// SQL 迁移文件整个作为一次执行，MySQL 需要 multiStatements，只在迁移单独打开的连接上开启
+// 应用的连接不开启，避免拼接的 SQL 被注入多条语句
+func openConn(db *gorm.DB) (*gorm.DB, func(), error) {
+	d, ok := db.Dialector.(*mysql.Dialector)
+	if !ok || d.DSN == "" {
+		return db, func() {}, nil
+	}
+	cfg, err := mysqldriver.ParseDSN(d.DSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.MultiStatements = true
+	conn, err := gorm.Open(mysql.Open(cfg.FormatDSN()), &gorm.Config{
+		Logger:         db.Logger,
+		NamingStrategy: db.NamingStrategy,
+		NowFunc:        db.NowFunc,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { sqlDB.Close() }, nil
+}
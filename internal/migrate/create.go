@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var nameInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+const goTemplate = `package %s
+
+import (
+	"tier-up/internal/migrate"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrate.Register(migrate.Migration{
+		Version: %d,
+		Name:    %q,
+		Up: func(tx *gorm.DB) error {
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+`
+
+// Create 在 dir 中新建迁移文件，版本号为当前 UTC 时间
+// goFile 为 true 时生成 Go 迁移，否则生成 up 和 down 两个 SQL 文件
+func Create(dir, name string, goFile bool, now time.Time) ([]string, error) {
+	slug := strings.Trim(nameInvalid.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return nil, errors.New("迁移名称不能为空")
+	}
+	version, _ := strconv.ParseInt(now.UTC().Format("20060102150405"), 10, 64)
+	base := filepath.Join(dir, fmt.Sprintf("%d_%s", version, slug))
+
+	files := map[string]string{}
+	var order []string
+	if goFile {
+		order = []string{base + ".go"}
+		files[order[0]] = fmt.Sprintf(goTemplate, filepath.Base(filepath.Clean(dir)), version, slug)
+	} else {
+		order = []string{base + ".up.sql", base + ".down.sql"}
+		files[order[0]] = fmt.Sprintf("-- %s\n", slug)
+		files[order[1]] = fmt.Sprintf("-- 回滚 %s\n", slug)
+	}
+	for _, path := range order {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("文件已存在: %s", path)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for _, path := range order {
+		if err := os.WriteFile(path, []byte(files[path]), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// lockKey 迁移锁标识
+const (
+	lockKey  = "tier-up:schema_migrations"
+	lockID   = int64(7438203649126401) // pg_advisory_lock 使用的 bigint
+	lockWait = -1                      // 一直等待其他实例完成迁移
+)
+
+// acquireLock 在独立连接上获取数据库会话级锁，返回释放函数
+// 锁与连接绑定，迁移使用连接池中的其他连接，释放后归还连接
+// SQLite 等不支持的数据库不加锁
+func acquireLock(ctx context.Context, db *gorm.DB) (func(), error) {
+	var lock, unlock string
+	var args []interface{}
+	checkResult := false
+	switch db.Dialector.Name() {
+	case "postgres":
+		lock, unlock = "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)"
+		args = []interface{}{lockID}
+	case "mysql":
+		lock, unlock = "SELECT GET_LOCK(?, ?)", "SELECT RELEASE_LOCK(?)"
+		args = []interface{}{lockKey, lockWait}
+		checkResult = true
+	case "sqlserver":
+		lock = "EXEC sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2"
+		unlock = "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'"
+		args = []interface{}{lockKey, lockWait}
+	default:
+		return func() {}, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := execLock(ctx, conn, checkResult, lock, args...); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() {
+		// 释放锁不使用已取消的 ctx
+		_, _ = conn.ExecContext(context.Background(), unlock, args[0])
+		conn.Close()
+	}, nil
+}
+
+func execLock(ctx context.Context, conn *sql.Conn, checkResult bool, query string, args ...interface{}) error {
+	if !checkResult {
+		_, err := conn.ExecContext(ctx, query, args...)
+		return err
+	}
+	var result sql.NullInt64
+	if err := conn.QueryRowContext(ctx, query, args...).Scan(&result); err != nil {
+		return err
+	}
+	// GET_LOCK 超时返回 0，出错返回 NULL
+	if !result.Valid || result.Int64 != 1 {
+		return errors.New("获取 GET_LOCK 失败")
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 版本化迁移，Version 通常为创建时间 20060102150405
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // 为空时不可回滚
+	Source  string                  // go | sql
+
+	// NoTransaction 不在事务中执行，如 Postgres 的 CREATE INDEX CONCURRENTLY
+	// SQL 文件首行为 -- migrate:no-transaction 时设置
+	NoTransaction bool
+}
+
+// SchemaMigration 已执行的迁移记录
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var (
+	mu         sync.Mutex
+	registered []Migration
+	sqlFS      []fs.FS
+)
+
+// Register 注册 Go 迁移，在迁移文件的 init 中调用
+func Register(m Migration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if m.Source == "" {
+		m.Source = "go"
+	}
+	registered = append(registered, m)
+}
+
+// RegisterFS 注册 SQL 迁移目录，文件名为 <version>_<name>.up.sql 和 <version>_<name>.down.sql
+func RegisterFS(fsys fs.FS) {
+	mu.Lock()
+	defer mu.Unlock()
+	sqlFS = append(sqlFS, fsys)
+}
+
+// Migrations 返回全部迁移，按版本排序
+func Migrations() ([]Migration, error) {
+	mu.Lock()
+	list := append([]Migration(nil), registered...)
+	fsList := append([]fs.FS(nil), sqlFS...)
+	mu.Unlock()
+
+	for _, fsys := range fsList {
+		sqlMigrations, err := LoadSQL(fsys)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, sqlMigrations...)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	for i, m := range list {
+		if m.Version <= 0 || m.Up == nil {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少版本号或 Up", m.Version, m.Name)
+		}
+		if i > 0 && list[i-1].Version == m.Version {
+			return nil, fmt.Errorf("迁移版本重复: %d (%s, %s)", m.Version, list[i-1].Name, m.Name)
+		}
+	}
+	return list, nil
+}
+
+// SQL 迁移文件名: <版本>_<名称>.up.sql，<版本>_<名称>.up.<数据库>.sql 只用于对应的数据库（gorm Dialector 名称）
+var sqlFileName = regexp.MustCompile(`^(\d+)_(.+?)\.(up|down)(?:\.(postgres|mysql|sqlserver|sqlite))?\.sql$`)
+
+const noTransaction = "-- migrate:no-transaction"
+
+// LoadSQL 读取目录中的 SQL 迁移
+func LoadSQL(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	type scripts struct {
+		name     string
+		up, down map[string]string // 数据库名称 -> SQL，空字符串为通用文件
+	}
+	byVersion := map[int64]*scripts{}
+	var versions []int64
+	for _, e := range entries {
+		match := sqlFileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		s, ok := byVersion[version]
+		if !ok {
+			s = &scripts{name: match[2], up: map[string]string{}, down: map[string]string{}}
+			byVersion[version] = s
+			versions = append(versions, version)
+		} else if s.name != match[2] {
+			return nil, fmt.Errorf("迁移 %d 的 SQL 文件名不一致", version)
+		}
+		if match[3] == "up" {
+			s.up[match[4]] = string(data)
+		} else {
+			s.down[match[4]] = string(data)
+		}
+	}
+	list := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		s := byVersion[v]
+		if len(s.up) == 0 {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少 up 文件", v, s.name)
+		}
+		m := Migration{Version: v, Name: s.name, Source: "sql", Up: execSQL(v, s.name, s.up)}
+		if len(s.down) > 0 {
+			m.Down = execSQL(v, s.name, s.down)
+		}
+		// 任一 up 文件首行声明时不使用事务
+		for _, content := range s.up {
+			if strings.HasPrefix(strings.TrimSpace(content), noTransaction) {
+				m.NoTransaction = true
+			}
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// execSQL 优先执行当前数据库专用的文件，没有时执行通用文件，整个文件作为一次执行，多条语句由驱动处理
+// MySQL 的 multiStatements 只在迁移连接上开启，见 openConn
+func execSQL(version int64, name string, scripts map[string]string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		content, ok := scripts[tx.Dialector.Name()]
+		if !ok {
+			if content, ok = scripts[""]; !ok {
+				return fmt.Errorf("迁移 %d_%s 没有适用于 %s 的 SQL 文件", version, name, tx.Dialector.Name())
+			}
+		}
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		return tx.Exec(content).Error
+	}
+}
+
+// Status 迁移状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Source    string     `json:"source"`
+	AppliedAt *time.Time `json:"applied_at"` // 为空表示未执行
+	Missing   bool       `json:"missing"`    // 已执行但代码中不存在
+}
+
+// Migrator 执行迁移
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration
+}
+
+// NewMigrator 使用已注册的全部迁移
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	list, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: list}, nil
+}
+
+// ErrIrreversible 迁移没有 Down
+var ErrIrreversible = errors.New("迁移不可回滚")
+
+// Up 执行未执行的迁移，steps 为 0 时全部执行，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.Migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if steps > 0 && len(done) >= steps {
+				break
+			}
+			err := m.run(db, mg, mg.Up, func(tx *gorm.DB) error {
+				return tx.Create(&SchemaMigration{Version: mg.Version, Name: mg.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("执行迁移 %d_%s 失败: %w", mg.Version, mg.Name, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 按版本倒序回滚已执行的迁移，steps 为 0 时全部回滚
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		known := map[int64]Migration{}
+		for _, mg := range m.Migrations {
+			known[mg.Version] = mg
+		}
+		versions := make([]int64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, v := range versions {
+			if steps > 0 && len(done) >= steps {
+				break
+			}
+			mg, ok := known[v]
+			if !ok {
+				return fmt.Errorf("迁移 %d_%s 已执行但代码中不存在，无法回滚", v, applied[v].Name)
+			}
+			if mg.Down == nil {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversible, mg.Version, mg.Name)
+			}
+			err := m.run(db, mg, mg.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&SchemaMigration{}, "version = ?", mg.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("回滚迁移 %d_%s 失败: %w", mg.Version, mg.Name, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status 返回全部迁移的执行状态，包括已执行但代码中不存在的版本
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	db := m.DB.WithContext(ctx)
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+	var list []Status
+	for _, mg := range m.Migrations {
+		s := Status{Version: mg.Version, Name: mg.Name, Source: mg.Source}
+		if r, ok := applied[mg.Version]; ok {
+			at := r.AppliedAt
+			s.AppliedAt = &at
+			delete(applied, mg.Version)
+		}
+		list = append(list, s)
+	}
+	for _, r := range applied {
+		at := r.AppliedAt
+		list = append(list, Status{Version: r.Version, Name: r.Name, AppliedAt: &at, Missing: true})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// withLock 获取迁移锁后执行，多个实例同时启动时只有一个执行迁移
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	unlock, err := acquireLock(ctx, m.DB)
+	if err != nil {
+		return fmt.Errorf("获取迁移锁失败: %w", err)
+	}
+	defer unlock()
+
+	conn, closeConn, err := openConn(m.DB)
+	if err != nil {
+		return fmt.Errorf("打开迁移连接失败: %w", err)
+	}
+	defer closeConn()
+
+	db := conn.WithContext(ctx)
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+	return fn(db)
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// run 执行迁移并记录，默认在同一事务中
+func (m *Migrator) run(db *gorm.DB, mg Migration, fn, record func(tx *gorm.DB) error) error {
+	if mg.NoTransaction {
+		if err := fn(db); err != nil {
+			return err
+		}
+		return record(db)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
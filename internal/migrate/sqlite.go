@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// WithoutForeignKeys 在事务中执行 fn，SQLite 执行期间关闭外键检查
+// SQLite 的 DropColumn 等操作通过新建表、复制数据、删除旧表完成，删除被引用的表会违反外键约束，重建的表也不保留索引
+// PRAGMA foreign_keys 在事务中不生效，使用的迁移需要设置 NoTransaction，fn 在同一连接上开启事务，提交前恢复索引并检查外键
+func WithoutForeignKeys(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if db.Dialector.Name() != "sqlite" {
+		return db.Transaction(fn)
+	}
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+		return conn.Transaction(func(tx *gorm.DB) error {
+			indexes, err := sqliteIndexes(tx)
+			if err != nil {
+				return err
+			}
+			if err := fn(tx); err != nil {
+				return err
+			}
+			if err := restoreIndexes(tx, indexes); err != nil {
+				return err
+			}
+			var violations []map[string]interface{}
+			if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				return fmt.Errorf("外键检查失败: %v", violations)
+			}
+			return nil
+		})
+	})
+}
+
+type sqliteIndex struct {
+	Name    string
+	Table   string
+	SQL     string
+	Columns []string `gorm:"-"`
+}
+
+// sqliteIndexes 显式创建的索引，自动创建的唯一索引等 sql 为空，随表结构重建
+func sqliteIndexes(tx *gorm.DB) ([]sqliteIndex, error) {
+	var indexes []sqliteIndex
+	err := tx.Raw("SELECT name, tbl_name AS `table`, sql FROM sqlite_master WHERE type = 'index' AND sql IS NOT NULL").
+		Scan(&indexes).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range indexes {
+		err := tx.Raw("SELECT name FROM pragma_index_info(?)", indexes[i].Name).Scan(&indexes[i].Columns).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indexes, nil
+}
+
+// restoreIndexes 重建后丢失的索引按原语句创建，表或列已删除的不再创建
+func restoreIndexes(tx *gorm.DB, indexes []sqliteIndex) error {
+	for _, idx := range indexes {
+		var exists int64
+		if err := tx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name = ?", idx.Name).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			continue
+		}
+		var columns []string
+		if err := tx.Raw("SELECT name FROM pragma_table_info(?)", idx.Table).Scan(&columns).Error; err != nil {
+			return err
+		}
+		if !containsAll(columns, idx.Columns) {
+			continue
+		}
+		if err := tx.Exec(idx.SQL).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsAll(set, items []string) bool {
+	if len(set) == 0 {
+		return false
+	}
+	has := make(map[string]bool, len(set))
+	for _, s := range set {
+		has[s] = true
+	}
+	for _, item := range items {
+		if !has[item] {
+			return false
+		}
+	}
+	return true
+}
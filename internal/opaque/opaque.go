@@ -0,0 +1,25 @@
+// Package opaque 不透明令牌，如刷新令牌、两步验证的登录令牌和恢复码
+// 数据库只保存摘要，令牌为高熵随机值，不需要加盐或慢哈希，使用 SHA-256 即可
+package opaque
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// New n 字节随机值，Base64 URL 编码
+func New(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash 保存到数据库的摘要，64 位十六进制
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,331 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"tier-up/internal/crud"
+	"tier-up/internal/route"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document OpenAPI 3.1 文档
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version"`
+	License     *License `json:"license,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+const securityName = "BearerAuth"
+
+// Build 根据路由注册表和已注册模型生成文档
+// swaggerJSON 为 swag 生成的 Swagger 2.0 文档，手写控制器的注释从中合并
+func Build(registry *route.Registry, models []*crud.Model, swaggerJSON string) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				// Swagger 2.0 只能声明为 apiKey，OpenAPI 3 直接声明为 Bearer 令牌
+				securityName: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	sw, err := parseSwagger(swaggerJSON)
+	if err != nil {
+		return nil, err
+	}
+	doc.Info = sw.Info
+	basePath := strings.TrimSuffix(sw.BasePath, "/")
+	if basePath != "" {
+		doc.Servers = []Server{{URL: basePath}}
+	}
+	for name, s := range sw.Definitions {
+		doc.Components.Schemas[name] = s
+	}
+
+	b := &builder{
+		doc:    doc,
+		gen:    newSchemaGenerator(doc.Components.Schemas),
+		models: map[string]*crud.Model{},
+	}
+	for _, m := range models {
+		b.models[m.Name] = m
+	}
+	b.addEnvelopes()
+
+	// 手写接口的 operationId 使用处理函数名，重名时由请求方法和路径生成
+	routes := registry.Routes()
+	actions := map[string]int{}
+	for _, r := range routes {
+		actions[r.Action]++
+	}
+
+	for _, r := range routes {
+		p := strings.TrimPrefix(r.Path, basePath)
+		path, params := convertPath(p)
+		method := strings.ToLower(r.Method)
+
+		var op *Operation
+		if m, ok := b.models[r.Model]; ok {
+			op, err = b.crudOperation(r, m, strings.Contains(p, ":"))
+			if err != nil {
+				return nil, err
+			}
+		} else if swOp := sw.operation(path, method); swOp != nil {
+			op = swOp
+		} else {
+			op = &Operation{Summary: r.Action, Responses: map[string]*Response{"200": {Description: "成功"}}}
+			for _, name := range params {
+				op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+		}
+		if op.OperationID == "" {
+			if r.Action != "" && actions[r.Action] == 1 {
+				op.OperationID = r.Action
+			} else {
+				op.OperationID = operationName("", method, path)
+			}
+		}
+		if len(op.Tags) == 0 && r.Tag != "" {
+			op.Tags = []string{r.Tag}
+		}
+		if r.Auth {
+			op.Security = []map[string][]string{{securityName: {}}}
+		} else {
+			op.Security = nil
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*Operation{}
+		}
+		doc.Paths[path][method] = op
+	}
+	return doc, nil
+}
+
+// Handler 返回文档的处理函数，文档只序列化一次
+func (d *Document) Handler() gin.HandlerFunc {
+	data, err := json.Marshal(d)
+	return func(c *gin.Context) {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": "生成文档失败: " + err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
+
+// convertPath 将 gin 路径参数转换为 OpenAPI 格式，:id -> {id}
+func convertPath(p string) (string, []string) {
+	var params []string
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			name := part[1:]
+			params = append(params, name)
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/"), params
+}
+
+type builder struct {
+	doc    *Document
+	gen    *schemaGenerator
+	models map[string]*crud.Model
+}
+
+// addEnvelopes 统一响应结构
+func (b *builder) addEnvelopes() {
+	b.doc.Components.Schemas["Response"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Description: "0 表示成功"},
+			"message": {Type: "string"},
+		},
+		Required: []string{"code", "message"},
+	}
+}
+
+// envelope 带数据的响应
+func (b *builder) envelope(name string, data *Schema) *Schema {
+	if _, ok := b.doc.Components.Schemas[name]; !ok {
+		b.doc.Components.Schemas[name] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code":    {Type: "integer", Description: "0 表示成功"},
+				"message": {Type: "string"},
+				"data":    data,
+			},
+			Required: []string{"code", "message"},
+		}
+	}
+	return ref(name)
+}
+
+func jsonBody(s *Schema, description string) *RequestBody {
+	return &RequestBody{
+		Description: description,
+		Required:    true,
+		Content:     map[string]MediaType{"application/json": {Schema: s}},
+	}
+}
+
+func jsonResponse(s *Schema, description string) map[string]*Response {
+	return map[string]*Response{
+		"200": {Description: description, Content: map[string]MediaType{"application/json": {Schema: s}}},
+		"400": {Description: "参数错误", Content: map[string]MediaType{"application/json": {Schema: ref("Response")}}},
+	}
+}
+
+// crudOperation 根据模型和动作生成 crud 接口文档
+func (b *builder) crudOperation(r route.Route, m *crud.Model, byPath bool) (*Operation, error) {
+	sch, err := m.Schema()
+	if err != nil {
+		return nil, err
+	}
+	model := b.gen.Schema(m.Type)
+	name := m.Name
+	op := &Operation{OperationID: m.Name + strings.ToUpper(r.Action[:1]) + r.Action[1:]}
+
+	var keyParams []*Parameter
+	keyObject := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range sch.PrimaryFields {
+		paramName := f.DBName
+		if len(sch.PrimaryFields) == 1 {
+			paramName = "id"
+		}
+		keySchema := &Schema{Type: "integer"}
+		if f.IndirectFieldType.Kind() == reflect.String {
+			keySchema = &Schema{Type: "string"}
+		}
+		keyParams = append(keyParams, &Parameter{Name: paramName, In: "path", Required: true, Description: f.DBName, Schema: keySchema})
+		keyObject.Properties[f.DBName] = keySchema
+		keyObject.Required = append(keyObject.Required, f.DBName)
+	}
+
+	switch r.Action {
+	case "create":
+		op.Summary = "创建 " + m.Name
+		op.RequestBody = jsonBody(b.gen.Schema(m.DTO), m.Name+" 数据")
+		op.Responses = jsonResponse(b.envelope(name+"Response", model), "创建成功")
+	case "update":
+		op.Summary = "更新 " + m.Name
+		op.Parameters = keyParams
+		op.RequestBody = jsonBody(b.gen.Schema(m.DTO), m.Name+" 数据")
+		op.Responses = jsonResponse(b.envelope(name+"Response", model), "更新成功")
+	case "delete":
+		op.Summary = "删除 " + m.Name
+		if byPath {
+			op.Parameters = keyParams
+		} else {
+			op.OperationID += "ByKey"
+			op.RequestBody = jsonBody(keyObject, m.Name+" 主键")
+		}
+		op.Responses = jsonResponse(ref("Response"), "删除成功")
+		op.Responses["404"] = &Response{Description: "记录不存在"}
+	case "page":
+		op.Summary = "分页查询 " + m.Name
+		op.Parameters = []*Parameter{
+			{Name: "page", In: "query", Description: "页码，从1开始", Schema: &Schema{Type: "integer", Minimum: float(1)}},
+			{Name: "limit", In: "query", Description: "每页数量", Schema: &Schema{Type: "integer", Minimum: float(1), Maximum: float(1000)}},
+			{Name: "sort", In: "query", Description: "排序字段，逗号分隔，- 前缀表示倒序，如 -created_at", Schema: &Schema{Type: "string"}},
+		}
+		// 过滤条件，字段名后可加 __ne、__gt、__gte、__lt、__lte、__like、__in
+		for _, f := range sch.Fields {
+			if f.DBName == "" || f.Tag.Get("json") == "-" {
+				continue
+			}
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:        f.DBName,
+				In:          "query",
+				Description: "按 " + f.DBName + " 过滤，支持 " + f.DBName + "__like 等操作",
+				Schema:      &Schema{Type: "string"},
+			})
+		}
+		page := &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"page":  {Type: "integer"},
+				"limit": {Type: "integer"},
+				"total": {Type: "integer", Format: "int64"},
+				"data":  {Type: "array", Items: model},
+			},
+		}
+		op.Responses = jsonResponse(b.envelope(name+"PageResponse", page), "获取列表成功")
+	default:
+		op.Summary = r.Action + " " + m.Name
+		op.Responses = map[string]*Response{"200": {Description: "成功"}}
+	}
+	return op, nil
+}
@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schema JSON Schema，OpenAPI 3.1 与 JSON Schema 2020-12 兼容
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // string，可为空时为 [type, "null"]
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaGenerator 通过反射生成 Schema，具名结构体放入 components
+// 反射结果覆盖 swag 生成的同名定义，以包含 binding 约束
+type schemaGenerator struct {
+	schemas   map[string]*Schema
+	generated map[string]bool
+}
+
+func newSchemaGenerator(schemas map[string]*Schema) *schemaGenerator {
+	return &schemaGenerator{schemas: schemas, generated: map[string]bool{}}
+}
+
+// refName 组件名，与 swag 一致，如 model.Menu
+func refName(t reflect.Type) string {
+	return t.String()
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Schema 返回类型的 Schema，具名结构体返回引用
+func (g *schemaGenerator) Schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	s := g.schema(t)
+	if nullable && s.Ref == "" {
+		if typ, ok := s.Type.(string); ok {
+			s.Type = []string{typ, "null"}
+		}
+	}
+	return s
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == deletedAtType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.Schema(t.Elem())}
+	case reflect.Struct:
+		// 自定义序列化的类型无法推断结构
+		if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+			return &Schema{}
+		}
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := refName(t)
+		if !g.generated[name] {
+			// 先标记，支持递归结构如菜单树
+			g.generated[name] = true
+			g.schemas[name] = g.object(t)
+		}
+		return ref(name)
+	}
+	return &Schema{}
+}
+
+// object 结构体展开为对象，嵌入的结构体字段合并到当前对象
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.fields(t, s)
+	return s
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonTag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(jsonTag, ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, s)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := g.Schema(f.Type)
+		if opts == "string" {
+			prop = &Schema{Type: "string"}
+		}
+		if required := applyBinding(prop, f.Tag.Get("binding")); required {
+			s.Required = append(s.Required, name)
+		}
+		if desc := f.Tag.Get("description"); desc != "" {
+			prop.Description = desc
+		} else if comment := gormComment(f.Tag.Get("gorm")); comment != "" && prop.Ref == "" {
+			prop.Description = comment
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyBinding 将 binding 校验规则转换为约束，返回是否必填
+func applyBinding(s *Schema, binding string) bool {
+	if binding == "" || s.Ref != "" {
+		return strings.Contains(binding, "required")
+	}
+	required := false
+	isString := s.Type == "string" || reflect.DeepEqual(s.Type, []string{"string", "null"})
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "min", "gte":
+			if n, err := strconv.Atoi(value); err == nil {
+				if isString {
+					s.MinLength = &n
+				} else {
+					s.Minimum = float(float64(n))
+				}
+			}
+		case "max", "lte":
+			if n, err := strconv.Atoi(value); err == nil {
+				if isString {
+					s.MaxLength = &n
+				} else {
+					s.Maximum = float(float64(n))
+				}
+			}
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				s.Enum = append(s.Enum, v)
+			}
+		}
+	}
+	return required
+}
+
+// gormComment 读取 gorm 标签中的 comment
+func gormComment(tag string) string {
+	for _, part := range strings.Split(tag, ";") {
+		if strings.HasPrefix(part, "comment:") {
+			return strings.TrimPrefix(part, "comment:")
+		}
+	}
+	return ""
+}
+
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// swagger swag 生成的 Swagger 2.0 文档，只解析合并需要的部分
+type swagger struct {
+	Info        Info                                   `json:"info"`
+	BasePath    string                                 `json:"basePath"`
+	Paths       map[string]map[string]swaggerOperation `json:"paths"`
+	Definitions map[string]*Schema                     `json:"definitions"`
+}
+
+type swaggerOperation struct {
+	Tags        []string                   `json:"tags"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description"`
+	Consumes    []string                   `json:"consumes"`
+	Produces    []string                   `json:"produces"`
+	Parameters  []swaggerParameter         `json:"parameters"`
+	Responses   map[string]swaggerResponse `json:"responses"`
+}
+
+type swaggerParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description"`
+	Required    bool          `json:"required"`
+	Type        string        `json:"type"`
+	Format      string        `json:"format"`
+	Items       *Schema       `json:"items"`
+	Enum        []interface{} `json:"enum"`
+	Schema      *Schema       `json:"schema"`
+}
+
+type swaggerResponse struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+func parseSwagger(doc string) (*swagger, error) {
+	sw := &swagger{}
+	if doc == "" {
+		return sw, nil
+	}
+	// 2.0 的定义位于 definitions，3.x 位于 components/schemas
+	doc = strings.ReplaceAll(doc, "#/definitions/", "#/components/schemas/")
+	if err := json.Unmarshal([]byte(doc), sw); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// operation 将 2.0 接口转换为 3.1 格式
+func (sw *swagger) operation(path, method string) *Operation {
+	o, ok := sw.Paths[path][method]
+	if !ok {
+		return nil
+	}
+	op := &Operation{
+		Tags:        o.Tags,
+		Summary:     o.Summary,
+		Description: o.Description,
+		Responses:   map[string]*Response{},
+	}
+	consumes := "application/json"
+	if len(o.Consumes) > 0 {
+		consumes = o.Consumes[0]
+	}
+	produces := "application/json"
+	if len(o.Produces) > 0 {
+		produces = o.Produces[0]
+	}
+
+	var form *Schema
+	for _, p := range o.Parameters {
+		switch p.In {
+		case "body":
+			op.RequestBody = &RequestBody{
+				Description: p.Description,
+				Required:    p.Required,
+				Content:     map[string]MediaType{consumes: {Schema: p.Schema}},
+			}
+		case "formData":
+			if form == nil {
+				form = &Schema{Type: "object", Properties: map[string]*Schema{}}
+			}
+			form.Properties[p.Name] = &Schema{Type: p.Type, Format: p.Format, Description: p.Description, Items: p.Items, Enum: p.Enum}
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+			}
+		default:
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required,
+				Schema:      &Schema{Type: p.Type, Format: p.Format, Items: p.Items, Enum: p.Enum},
+			})
+		}
+	}
+	if form != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{consumes: {Schema: form}}}
+	}
+
+	for code, r := range o.Responses {
+		resp := &Response{Description: r.Description}
+		if r.Schema != nil {
+			resp.Content = map[string]MediaType{produces: {Schema: r.Schema}}
+		}
+		op.Responses[code] = resp
+	}
+	return op
+}
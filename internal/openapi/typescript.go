@@ -0,0 +1,451 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TypeScript 根据文档生成前端使用的类型定义和 fetch 客户端
+// 所有接口返回统一的 ApiResponse，文档中未使用响应结构的接口按 data 类型包装
+func TypeScript(doc *Document) []byte {
+	g := &tsGenerator{doc: doc, names: tsNames(doc.Components.Schemas)}
+	var b strings.Builder
+
+	b.WriteString("// Code generated by tier gen ts. DO NOT EDIT.\n")
+	b.WriteString("// 由 go run ./cmd/tier gen ts 根据路由注册表和模型生成\n\n")
+	b.WriteString(tsRuntime)
+
+	// 类型定义
+	schemaNames := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		schemaNames = append(schemaNames, name)
+	}
+	sort.Slice(schemaNames, func(i, j int) bool { return g.names[schemaNames[i]] < g.names[schemaNames[j]] })
+	for _, name := range schemaNames {
+		if name == "Response" {
+			// 不带数据的响应即 ApiResponse
+			continue
+		}
+		s := doc.Components.Schemas[name]
+		if s.Description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", s.Description)
+		}
+		if s.Type == "object" && s.Ref == "" && len(s.Properties) > 0 {
+			fmt.Fprintf(&b, "export interface %s %s\n\n", g.names[name], g.object(s, ""))
+			continue
+		}
+		fmt.Fprintf(&b, "export type %s = %s\n\n", g.names[name], g.typeOf(s, ""))
+	}
+
+	// 接口
+	b.WriteString("export function createClient(options: ClientOptions = {}) {\n")
+	b.WriteString("  const request = createRequest(options)\n")
+	b.WriteString("  return {\n")
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		methods := make([]string, 0, len(doc.Paths[p]))
+		for m := range doc.Paths[p] {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			g.operation(&b, p, m, doc.Paths[p][m])
+		}
+	}
+	b.WriteString("  }\n}\n\n")
+	b.WriteString("export type Client = ReturnType<typeof createClient>\n")
+	return []byte(b.String())
+}
+
+// tsRuntime 请求封装，与生成的接口一起输出，前端无需额外依赖
+const tsRuntime = `/** 统一响应结构，code 为 0 表示成功 */
+export interface ApiResponse<T = unknown> {
+  code: number
+  message: string
+  data?: T
+}
+
+export interface ClientOptions {
+  /** 接口前缀，如 http://localhost:88/api/v1 */
+  baseURL?: string
+  /** 返回当前的访问令牌（登录返回的 accessToken），请求时以 Bearer 方式放入 Authorization 头 */
+  token?: () => string | null | undefined
+  headers?: Record<string, string>
+  fetch?: typeof fetch
+}
+
+export type Query = Record<string, string | number | boolean | null | undefined>
+
+/** HTTP 状态码非 2xx 时抛出 */
+export class ApiError extends Error {
+  status: number
+  body: ApiResponse | undefined
+
+  constructor(status: number, body: ApiResponse | undefined) {
+    super(body?.message ?? ` + "`HTTP ${status}`" + `)
+    this.status = status
+    this.body = body
+  }
+}
+
+function createRequest(options: ClientOptions) {
+  const doFetch = options.fetch ?? fetch
+  return async <T>(method: string, path: string, query?: Query, body?: unknown, init?: RequestInit): Promise<T> => {
+    let url = (options.baseURL ?? '') + path
+    if (query) {
+      const params = new URLSearchParams()
+      for (const [key, value] of Object.entries(query)) {
+        if (value !== undefined && value !== null) params.append(key, String(value))
+      }
+      const qs = params.toString()
+      if (qs) url += '?' + qs
+    }
+    const headers: Record<string, string> = { ...options.headers }
+    const token = options.token?.()
+    if (token) headers['Authorization'] = 'Bearer ' + token
+    if (body !== undefined) headers['Content-Type'] = 'application/json'
+    const res = await doFetch(url, {
+      ...init,
+      method,
+      headers: { ...headers, ...(init?.headers as Record<string, string> | undefined) },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    const text = await res.text()
+    const data = text ? JSON.parse(text) : undefined
+    if (!res.ok) throw new ApiError(res.status, data)
+    return data as T
+  }
+}
+
+`
+
+type tsGenerator struct {
+	doc   *Document
+	names map[string]string // 组件名 -> TypeScript 类型名
+}
+
+var tsInvalid = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// tsReserved 运行时代码和全局类型使用的名称
+var tsReserved = map[string]bool{
+	"ApiResponse": true, "ApiError": true, "ClientOptions": true, "Query": true, "Client": true,
+	"Response": true, "Request": true, "RequestInit": true, "Record": true,
+}
+
+// tsNames 去掉包名作为类型名，如 model.User -> User，重名或与保留名称冲突时保留包名
+func tsNames(schemas map[string]*Schema) map[string]string {
+	count := map[string]int{}
+	short := func(name string) string {
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		return tsIdent(name)
+	}
+	for name := range schemas {
+		count[short(name)]++
+	}
+	names := map[string]string{}
+	for name := range schemas {
+		s := short(name)
+		switch {
+		case name == "Response":
+			names[name] = "ApiResponse"
+		case count[s] == 1 && !tsReserved[s]:
+			names[name] = s
+		case tsReserved[tsIdent(name)]:
+			names[name] = tsIdent(name) + "Schema"
+		default:
+			names[name] = tsIdent(name)
+		}
+	}
+	return names
+}
+
+// tsIdent 转换为合法的类型名，如 model.User -> ModelUser
+func tsIdent(s string) string {
+	var b strings.Builder
+	for _, part := range tsInvalid.Split(s, -1) {
+		if part == "" {
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "T" + b.String()
+	}
+	return b.String()
+}
+
+func (g *tsGenerator) refName(ref string) string {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if n, ok := g.names[name]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+func (g *tsGenerator) resolve(s *Schema) *Schema {
+	if s != nil && s.Ref != "" {
+		return g.doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// typeOf Schema 转换为 TypeScript 类型，indent 为对象字面量的缩进
+func (g *tsGenerator) typeOf(s *Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return g.refName(s.Ref)
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			if str, ok := v.(string); ok {
+				values = append(values, strconv.Quote(str))
+			} else {
+				values = append(values, fmt.Sprint(v))
+			}
+		}
+		return strings.Join(values, " | ")
+	}
+
+	var types []string
+	switch t := s.Type.(type) {
+	case string:
+		types = []string{t}
+	case []string:
+		types = t
+	case []interface{}:
+		for _, v := range t {
+			types = append(types, fmt.Sprint(v))
+		}
+	}
+	if len(types) == 0 {
+		if len(s.Properties) > 0 {
+			types = []string{"object"}
+		} else {
+			return "unknown"
+		}
+	}
+
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case "string":
+			out = append(out, "string")
+		case "integer", "number":
+			out = append(out, "number")
+		case "boolean":
+			out = append(out, "boolean")
+		case "null":
+			out = append(out, "null")
+		case "array":
+			item := g.typeOf(s.Items, indent)
+			if strings.ContainsAny(item, " |") {
+				item = "(" + item + ")"
+			}
+			out = append(out, item+"[]")
+		case "object":
+			out = append(out, g.object(s, indent))
+		default:
+			out = append(out, "unknown")
+		}
+	}
+	return strings.Join(out, " | ")
+}
+
+// object 对象类型，只有 additionalProperties 时为 Record
+func (g *tsGenerator) object(s *Schema, indent string) string {
+	if len(s.Properties) == 0 {
+		if extra, ok := s.AdditionalProperties.(*Schema); ok {
+			return "Record<string, " + g.typeOf(extra, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		p := s.Properties[k]
+		if p.Description != "" {
+			fmt.Fprintf(&b, "%s  /** %s */\n", indent, p.Description)
+		}
+		opt := "?"
+		if required[k] {
+			opt = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s\n", indent, tsKey(k), opt, g.typeOf(p, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsKey(k string) string {
+	if tsIdentifier.MatchString(k) {
+		return k
+	}
+	return strconv.Quote(k)
+}
+
+// isEnvelope 是否为统一响应结构
+func (g *tsGenerator) isEnvelope(s *Schema) bool {
+	s = g.resolve(s)
+	if s == nil {
+		return false
+	}
+	_, code := s.Properties["code"]
+	_, message := s.Properties["message"]
+	return code && message
+}
+
+// responseType 成功响应的类型，未使用响应结构的按 data 包装
+func (g *tsGenerator) responseType(op *Operation) string {
+	var schema *Schema
+	for _, code := range []string{"200", "201"} {
+		if r, ok := op.Responses[code]; ok {
+			if mt, ok := r.Content["application/json"]; ok {
+				schema = mt.Schema
+			}
+			break
+		}
+	}
+	switch {
+	case schema == nil:
+		return "ApiResponse"
+	case g.isEnvelope(schema):
+		return g.typeOf(schema, "    ")
+	case schema.Ref == "" && len(schema.Properties) == 0 && (schema.Type == "object" || schema.Type == nil):
+		// 文档中的 map[string]interface{} 描述的是整个响应
+		return "ApiResponse"
+	}
+	return "ApiResponse<" + g.typeOf(schema, "    ") + ">"
+}
+
+// operation 输出单个接口方法
+func (g *tsGenerator) operation(b *strings.Builder, path, method string, op *Operation) {
+	var args, pathParams []string
+	var query []*Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			name := tsVar(p.Name)
+			args = append(args, name+": "+g.paramType(p))
+			pathParams = append(pathParams, p.Name)
+		case "query":
+			query = append(query, p)
+		}
+	}
+	bodyArg := "undefined"
+	if op.RequestBody != nil {
+		var schema *Schema
+		for _, mt := range op.RequestBody.Content {
+			schema = mt.Schema
+			break
+		}
+		opt := ""
+		if !op.RequestBody.Required {
+			opt = "?"
+		}
+		args = append(args, "body"+opt+": "+g.typeOf(schema, "    "))
+		bodyArg = "body"
+	}
+	queryArg := "undefined"
+	if len(query) > 0 {
+		var q strings.Builder
+		q.WriteString("{\n")
+		required := false
+		for _, p := range query {
+			if p.Description != "" {
+				fmt.Fprintf(&q, "      /** %s */\n", p.Description)
+			}
+			opt := "?"
+			if p.Required {
+				opt = ""
+				required = true
+			}
+			fmt.Fprintf(&q, "      %s%s: %s\n", tsKey(p.Name), opt, g.paramType(p))
+		}
+		// crud 分页接口支持 字段__操作符 形式的过滤条件
+		q.WriteString("    } & Query")
+		opt := "?"
+		if required {
+			opt = ""
+		}
+		args = append(args, "query"+opt+": "+q.String())
+		queryArg = "query"
+	}
+	args = append(args, "init?: RequestInit")
+
+	url := "'" + path + "'"
+	if len(pathParams) > 0 {
+		u := path
+		for _, p := range pathParams {
+			u = strings.ReplaceAll(u, "{"+p+"}", "${encodeURIComponent(String("+tsVar(p)+"))}")
+		}
+		url = "`" + u + "`"
+	}
+
+	doc := op.Summary
+	if doc == "" {
+		doc = op.Description
+	}
+	if doc != "" {
+		fmt.Fprintf(b, "    /** %s */\n", doc)
+	}
+	fmt.Fprintf(b, "    %s: (%s) =>\n", operationName(op.OperationID, method, path), strings.Join(args, ", "))
+	fmt.Fprintf(b, "      request<%s>('%s', %s, %s, %s, init),\n", g.responseType(op), strings.ToUpper(method), url, queryArg, bodyArg)
+}
+
+func (g *tsGenerator) paramType(p *Parameter) string {
+	if p.Schema == nil {
+		return "string"
+	}
+	return g.typeOf(p.Schema, "      ")
+}
+
+// tsVar 参数名转换为变量名，如 user_id -> userId
+func tsVar(name string) string {
+	id := tsIdent(name)
+	r := []rune(id)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// operationName 方法名使用 operationId，如 MenuPage -> menuPage，缺少时由请求方法和路径生成
+func operationName(operationID, method, path string) string {
+	if operationID != "" {
+		return tsVar(operationID)
+	}
+	name := method
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") {
+			name += "_by_" + strings.Trim(seg, "{}")
+			continue
+		}
+		name += "_" + seg
+	}
+	return tsVar(name)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type commitKey struct{}
+
+// afterCommit 事务提交后执行的函数
+type afterCommit struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (h *afterCommit) add(fns ...func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns = append(h.fns, fns...)
+}
+
+func (h *afterCommit) run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// AfterCommit 当前事务提交后执行 fn，事务回滚时不执行，不在事务中时立即执行
+// 用于更新内存缓存等不能回滚的操作
+func AfterCommit(ctx context.Context, fn func()) {
+	if h, ok := ctx.Value(commitKey{}).(*afterCommit); ok {
+		h.add(fn)
+		return
+	}
+	fn()
+}
+
+// AfterCommitDB 同 AfterCommit，事务从连接的 ctx 中获取
+func AfterCommitDB(db *gorm.DB, fn func()) {
+	AfterCommit(db.Statement.Context, fn)
+}
+
+// InTransaction ctx 是否在 Transaction 中
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(commitKey{}).(*afterCommit)
+	return ok
+}
+
+// DeferCommit 不通过 Transaction 开启的事务（如 gorm 的默认事务）中使用
+// 返回的 ctx 收集 AfterCommit 注册的函数，事务提交后调用 run 执行
+func DeferCommit(ctx context.Context) (_ context.Context, run func()) {
+	hooks := &afterCommit{}
+	return context.WithValue(ctx, commitKey{}, hooks), hooks.run
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type replicaKey struct{}
+
+// replicaSetting 语句允许读副本
+const replicaSetting = "tier:replica"
+
+// Replica 标记 ctx 中的读操作使用只读副本，用于可以接受复制延迟的列表和报表查询
+// 事务中或已经由 Primary 标记时仍读主库
+func Replica(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(replicaKey{}).(bool); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, replicaKey{}, true)
+}
+
+// Primary 标记 ctx 中的读操作使用主库，用于写入后需要立即读到结果的场景
+func Primary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, false)
+}
+
+// ReadPrimaryByDefault 注册 dbresolver 后调用，未标记 Replica 的查询读主库
+// dbresolver 默认所有查询读副本，登录、写后读等场景会读到延迟的数据
+func ReadPrimaryByDefault(db *gorm.DB) error {
+	primary := func(db *gorm.DB) {
+		if _, ok := db.Get(replicaSetting); !ok {
+			dbresolver.Write.ModifyStatement(db.Statement)
+		}
+	}
+	// 在 dbresolver 选择连接之后、执行之前切换回主库
+	if err := db.Callback().Query().After("gorm:db_resolver").Before("gorm:query").Register("tier:read_primary", primary); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:db_resolver").Before("gorm:row").Register("tier:read_primary", primary); err != nil {
+		return err
+	}
+	return db.Callback().Raw().After("gorm:db_resolver").Before("gorm:raw").Register("tier:read_primary", primary)
+}
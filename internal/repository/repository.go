@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"tier-up/internal/db/driver"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ErrInvalidQuery 过滤或排序参数不合法
+var ErrInvalidQuery = errors.New("无效的查询参数")
+
+// Scope 查询条件
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Where 条件查询
+func Where(query interface{}, args ...interface{}) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// Preload 预加载关联
+func Preload(query string, args ...interface{}) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(query, args...)
+	}
+}
+
+// Filter 列表过滤条件
+type Filter struct {
+	Field string      // 字段名，支持结构体字段或数据库列名
+	Op    string      // eq ne gt gte lt lte like in，默认 eq
+	Value interface{} // 过滤值，in 时为切片
+}
+
+// ListOptions 列表查询参数
+type ListOptions struct {
+	Filters []Filter
+	Sort    []string // 排序字段，"-" 前缀表示倒序，如 -created_at
+	Page    int      // 从1开始，为0时不分页
+	Limit   int
+	Scopes  []Scope
+}
+
+// Repository 通用数据访问
+type Repository[T any] struct {
+	DB *gorm.DB
+}
+
+// NewRepository 创建数据访问对象
+func NewRepository[T any](db *gorm.DB) *Repository[T] {
+	return &Repository[T]{DB: db}
+}
+
+type txKey struct{}
+
+// Transaction 开启事务，fn 内使用 ctx 的仓储操作都在同一事务中执行
+// 嵌套调用使用保存点，AfterCommit 注册的函数在最外层事务提交后执行
+func Transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	parent, nested := ctx.Value(commitKey{}).(*afterCommit)
+	hooks := &afterCommit{}
+	ctx = context.WithValue(ctx, commitKey{}, hooks)
+
+	conn := db.WithContext(ctx)
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		conn = tx
+	}
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		// 事务连接也带上本层的 ctx，通过连接调用 AfterCommit 时注册到本层
+		return fn(context.WithValue(ctx, txKey{}, tx.WithContext(ctx)))
+	})
+	if err != nil {
+		return err
+	}
+	if nested {
+		parent.add(hooks.fns...)
+		return nil
+	}
+	hooks.run()
+	return nil
+}
+
+// Conn 返回当前上下文的连接，存在事务时返回事务
+// 配置了只读副本时，Replica 标记的 ctx 读副本，其他情况读主库
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	if replica, _ := ctx.Value(replicaKey{}).(bool); replica {
+		return db.WithContext(ctx).Set(replicaSetting, true).Clauses(dbresolver.Read)
+	}
+	return db.WithContext(ctx)
+}
+
+// Transaction 开启事务
+func (r *Repository[T]) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Transaction(ctx, r.DB, fn)
+}
+
+// Conn 返回当前上下文的连接
+func (r *Repository[T]) Conn(ctx context.Context) *gorm.DB {
+	return Conn(ctx, r.DB)
+}
+
+// Key 复合主键，列名 -> 值
+type Key = map[string]interface{}
+
+// Get 通过主键获取，id 为数字主键值或 Key
+// 字符串主键必须使用 Key，否则会被当作SQL条件
+func (r *Repository[T]) Get(ctx context.Context, id interface{}, scopes ...Scope) (*T, error) {
+	var entity T
+	if err := r.Conn(ctx).Scopes(scopes...).First(&entity, id).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// First 获取第一条匹配记录
+func (r *Repository[T]) First(ctx context.Context, scopes ...Scope) (*T, error) {
+	var entity T
+	if err := r.Conn(ctx).Scopes(scopes...).First(&entity).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Find 获取全部匹配记录
+func (r *Repository[T]) Find(ctx context.Context, scopes ...Scope) ([]T, error) {
+	var list []T
+	if err := r.Conn(ctx).Scopes(scopes...).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Count 统计匹配记录数
+func (r *Repository[T]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	var total int64
+	if err := r.Conn(ctx).Model(new(T)).Scopes(scopes...).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Exists 判断是否存在匹配记录
+func (r *Repository[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	total, err := r.Count(ctx, scopes...)
+	return total > 0, err
+}
+
+// List 按过滤、排序、分页条件查询，返回当前页数据和总数
+func (r *Repository[T]) List(ctx context.Context, opts ListOptions) ([]T, int64, error) {
+	sch, err := r.Schema()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := r.Conn(ctx).Model(new(T)).Scopes(opts.Scopes...)
+	for _, f := range opts.Filters {
+		field := sch.LookUpField(f.Field)
+		if field == nil || field.DBName == "" {
+			return nil, 0, fmt.Errorf("%w: 未知的过滤字段 %s", ErrInvalidQuery, f.Field)
+		}
+		if query, err = applyFilter(query, field.DBName, f); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// 统计与查询复用同一组条件
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, s := range opts.Sort {
+		desc := strings.HasPrefix(s, "-")
+		field := sch.LookUpField(strings.TrimPrefix(s, "-"))
+		if field == nil || field.DBName == "" {
+			return nil, 0, fmt.Errorf("%w: 未知的排序字段 %s", ErrInvalidQuery, s)
+		}
+		order := field.DBName
+		if desc {
+			order += " DESC"
+		}
+		query = query.Order(order)
+	}
+
+	if opts.Page > 0 && opts.Limit > 0 {
+		query = query.Limit(opts.Limit).Offset((opts.Page - 1) * opts.Limit)
+	}
+
+	var list []T
+	if err := query.Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Create 新增
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	return r.Conn(ctx).Create(entity).Error
+}
+
+// Update 更新非零值字段，指定 columns 时只更新这些列，零值也会写入
+func (r *Repository[T]) Update(ctx context.Context, entity *T, columns ...string) error {
+	db := r.Conn(ctx).Model(entity)
+	if len(columns) > 0 {
+		db = db.Select(columns)
+	}
+	return db.Updates(entity).Error
+}
+
+// Save 保存全部字段
+func (r *Repository[T]) Save(ctx context.Context, entity *T) error {
+	return r.Conn(ctx).Save(entity).Error
+}
+
+// Delete 通过主键删除，id 为数字主键值或 Key
+func (r *Repository[T]) Delete(ctx context.Context, id interface{}) error {
+	result := r.Conn(ctx).Delete(new(T), id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PrimaryFields 返回主键字段，复合主键按定义顺序返回
+func (r *Repository[T]) PrimaryFields() ([]*schema.Field, error) {
+	sch, err := r.Schema()
+	if err != nil {
+		return nil, err
+	}
+	if len(sch.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("模型 %s 未定义主键", sch.Name)
+	}
+	return sch.PrimaryFields, nil
+}
+
+// Schema 返回模型的结构信息
+func (r *Repository[T]) Schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+func applyFilter(db *gorm.DB, column string, f Filter) (*gorm.DB, error) {
+	switch f.Op {
+	case "", "eq":
+		return db.Where(column+" = ?", f.Value), nil
+	case "ne":
+		return db.Where(column+" <> ?", f.Value), nil
+	case "gt":
+		return db.Where(column+" > ?", f.Value), nil
+	case "gte":
+		return db.Where(column+" >= ?", f.Value), nil
+	case "lt":
+		return db.Where(column+" < ?", f.Value), nil
+	case "lte":
+		return db.Where(column+" <= ?", f.Value), nil
+	case "like":
+		return db.Where(driver.ILike(db, column), "%"+fmt.Sprint(f.Value)+"%"), nil
+	case "in":
+		return db.Where(column+" IN ?", f.Value), nil
+	}
+	return nil, fmt.Errorf("%w: 不支持的过滤操作 %s", ErrInvalidQuery, f.Op)
+}
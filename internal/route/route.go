@@ -0,0 +1,195 @@
+package route
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route 路由信息
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`            // 完整路径，gin 风格，如 /api/v1/user/update/:id
+	Model  string `json:"model,omitempty"` // crud 生成的路由对应的模型
+	Action string `json:"action"`          // crud 动作或处理函数名
+	Tag    string `json:"tag"`             // Swagger 标签
+	Auth   bool   `json:"auth"`            // 是否需要登录
+	RBAC   bool   `json:"rbac"`            // 是否需要权限验证
+}
+
+// Meta 路由元数据，由路由组向下继承
+type Meta struct {
+	Model  string
+	Action string
+	Tag    string
+	Auth   bool
+	RBAC   bool
+}
+
+// Registry 路由注册表
+type Registry struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRegistry 创建路由注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add 记录路由
+func (r *Registry) Add(route Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route)
+}
+
+// Routes 返回全部路由，按路径和方法排序
+func (r *Registry) Routes() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	routes := make([]Route, len(r.routes))
+	copy(routes, r.routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Find 按方法和路径查找路由，路径为注册时的模板路径
+func (r *Registry) Find(method, fullPath string) (Route, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, route := range r.routes {
+		if route.Method == method && route.Path == fullPath {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// Group 记录路由信息的路由组
+type Group struct {
+	group    *gin.RouterGroup
+	registry *Registry
+	meta     Meta
+}
+
+// Group 包装 gin 路由组
+func (r *Registry) Group(g *gin.RouterGroup) *Group {
+	return &Group{group: g, registry: r}
+}
+
+// Gin 返回底层 gin 路由组
+func (g *Group) Gin() *gin.RouterGroup {
+	return g.group
+}
+
+// Group 创建子路由组，继承元数据
+func (g *Group) Group(relativePath string, handlers ...gin.HandlerFunc) *Group {
+	return &Group{group: g.group.Group(relativePath, handlers...), registry: g.registry, meta: g.meta}
+}
+
+// Use 添加中间件
+func (g *Group) Use(middleware ...gin.HandlerFunc) *Group {
+	g.group.Use(middleware...)
+	return g
+}
+
+// Auth 创建需要登录的子路由组
+func (g *Group) Auth(middleware ...gin.HandlerFunc) *Group {
+	child := g.Group("", middleware...)
+	child.meta.Auth = true
+	return child
+}
+
+// RBAC 创建需要权限验证的子路由组
+func (g *Group) RBAC(middleware ...gin.HandlerFunc) *Group {
+	child := g.Auth(middleware...)
+	child.meta.RBAC = true
+	return child
+}
+
+// With 返回覆盖了元数据的路由组，共用同一个 gin 路由组
+func (g *Group) With(meta Meta) *Group {
+	m := g.meta
+	if meta.Model != "" {
+		m.Model = meta.Model
+	}
+	if meta.Action != "" {
+		m.Action = meta.Action
+	}
+	if meta.Tag != "" {
+		m.Tag = meta.Tag
+	}
+	return &Group{group: g.group, registry: g.registry, meta: m}
+}
+
+// Tag 设置 Swagger 标签
+func (g *Group) Tag(tag string) *Group {
+	return g.With(Meta{Tag: tag})
+}
+
+// Handle 注册并记录路由
+func (g *Group) Handle(method, relativePath string, handlers ...gin.HandlerFunc) {
+	g.group.Handle(method, relativePath, handlers...)
+
+	action := g.meta.Action
+	if action == "" && len(handlers) > 0 {
+		action = handlerName(handlers[len(handlers)-1])
+	}
+	g.registry.Add(Route{
+		Method: method,
+		Path:   joinPaths(g.group.BasePath(), relativePath),
+		Model:  g.meta.Model,
+		Action: action,
+		Tag:    g.meta.Tag,
+		Auth:   g.meta.Auth,
+		RBAC:   g.meta.RBAC,
+	})
+}
+
+func (g *Group) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle("GET", relativePath, handlers...)
+}
+
+func (g *Group) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle("POST", relativePath, handlers...)
+}
+
+func (g *Group) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle("PUT", relativePath, handlers...)
+}
+
+func (g *Group) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle("DELETE", relativePath, handlers...)
+}
+
+// handlerName 处理函数名，如 (*UserController).Login-fm -> Login
+func handlerName(h gin.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+	finalPath := path.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
@@ -0,0 +1,331 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// 通过 AST 定位插入位置，再按偏移量插入源码，保留原有注释和格式
+
+type insertion struct {
+	offset int
+	text   string
+}
+
+type source struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+}
+
+func parse(src []byte) (*source, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	return &source{fset: fset, file: f, src: src}, nil
+}
+
+func (s *source) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+// text 节点对应的源码
+func (s *source) text(n ast.Node) string {
+	return string(s.src[s.offset(n.Pos()):s.offset(n.End())])
+}
+
+// apply 从后往前插入，避免偏移量失效
+func (s *source) apply(ins []insertion) ([]byte, error) {
+	sort.SliceStable(ins, func(i, j int) bool { return ins[i].offset > ins[j].offset })
+	out := append([]byte(nil), s.src...)
+	for _, in := range ins {
+		out = append(out[:in.offset], append([]byte(in.text), out[in.offset:]...)...)
+	}
+	return format.Source(out)
+}
+
+func (s *source) funcDecl(name string) *ast.FuncDecl {
+	for _, d := range s.file.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// callStmts 函数体中形如 recv.method(...) 的语句，包括嵌套的代码块
+func callStmts(body *ast.BlockStmt, recv, method string) []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(body, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return true
+		}
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if isSelector(call.Fun, recv, method) {
+			calls = append(calls, call)
+		}
+		return true
+	})
+	return calls
+}
+
+// isSelector 判断表达式是否为 x.sel，泛型实例化如 x.sel[T] 也匹配
+func isSelector(e ast.Expr, x, sel string) bool {
+	switch v := e.(type) {
+	case *ast.IndexExpr:
+		e = v.X
+	case *ast.IndexListExpr:
+		e = v.X
+	}
+	s, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := s.X.(*ast.Ident)
+	return ok && id.Name == x && s.Sel.Name == sel
+}
+
+// addRegister 在 internal/app/models.go 的 init 中注册模型
+func addRegister(src []byte, m *Module) ([]byte, error) {
+	s, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	fn := s.funcDecl("init")
+	if fn == nil {
+		return nil, errors.New("未找到 init 函数")
+	}
+	calls := callStmts(fn.Body, "crud", "Register")
+	for _, call := range calls {
+		if idx, ok := call.Fun.(*ast.IndexListExpr); ok && len(idx.Indices) > 0 && isSelector(idx.Indices[0], "model", m.Name) {
+			return src, nil
+		}
+	}
+	// 已作为内部表注册的模型不再生成路由
+	for _, call := range callStmts(fn.Body, "crud", "RegisterTable") {
+		if idx, ok := call.Fun.(*ast.IndexExpr); ok && isSelector(idx.Index, "model", m.Name) {
+			return nil, fmt.Errorf("模型 %s 已通过 crud.RegisterTable 注册为内部表", m.Name)
+		}
+	}
+
+	opts := "crud.Options{}"
+	switch m.Access {
+	case AccessAuth:
+		opts = "crud.Options{Access: crud.AccessAuth}"
+	case AccessPublic:
+		opts = "crud.Options{Access: crud.AccessPublic}"
+	}
+	stmt := fmt.Sprintf("crud.Register[model.%s, model.%sReq](%s)", m.Name, m.Name, opts)
+
+	offset := s.offset(fn.Body.Rbrace)
+	text := "\t" + stmt + "\n"
+	if len(calls) > 0 {
+		offset = s.offset(calls[len(calls)-1].End())
+		text = "\n\t" + stmt
+	}
+	return s.apply([]insertion{{offset, text}})
+}
+
+// addProviders 在 di.BuildContainer 中注册数据访问、服务和控制器
+// 分别插入到同类 Provide 调用之后
+func addProviders(src []byte, m *Module) ([]byte, error) {
+	s, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	fn := s.funcDecl("BuildContainer")
+	if fn == nil {
+		return nil, errors.New("未找到 BuildContainer 函数")
+	}
+	calls := callStmts(fn.Body, "container", "Provide")
+
+	providers := []struct {
+		pkg, fn, expr string
+	}{
+		{"repository", "NewRepository", fmt.Sprintf("repository.NewRepository[model.%s]", m.Name)},
+		{"service", "New" + m.Name + "Service", fmt.Sprintf("service.New%sService", m.Name)},
+		{"controller", "New" + m.Name + "Controller", fmt.Sprintf("controller.New%sController", m.Name)},
+	}
+
+	var ins []insertion
+	for _, p := range providers {
+		var last *ast.CallExpr
+		exists := false
+		for _, call := range calls {
+			if len(call.Args) != 1 {
+				continue
+			}
+			arg := call.Args[0]
+			if s.text(arg) == p.expr {
+				exists = true
+				break
+			}
+			if sel, ok := unwrapIndex(arg).(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Name == p.pkg {
+					last = call
+				}
+			}
+		}
+		if exists {
+			continue
+		}
+		stmt := "container.Provide(" + p.expr + ")"
+		if last != nil {
+			ins = append(ins, insertion{s.offset(last.End()), "\n\t" + stmt})
+			continue
+		}
+		// 没有同类注册时插入到 return 之前
+		ret := lastReturn(fn.Body)
+		if ret == nil {
+			return nil, errors.New("未找到 BuildContainer 的返回语句")
+		}
+		ins = append(ins, insertion{s.offset(ret.Pos()), stmt + "\n\t"})
+	}
+	if len(ins) == 0 {
+		return src, nil
+	}
+	return s.apply(ins)
+}
+
+func unwrapIndex(e ast.Expr) ast.Expr {
+	switch v := e.(type) {
+	case *ast.IndexExpr:
+		return v.X
+	case *ast.IndexListExpr:
+		return v.X
+	}
+	return e
+}
+
+func lastReturn(body *ast.BlockStmt) *ast.ReturnStmt {
+	for i := len(body.List) - 1; i >= 0; i-- {
+		if ret, ok := body.List[i].(*ast.ReturnStmt); ok {
+			return ret
+		}
+	}
+	return nil
+}
+
+// addRoute 在 SetupDigRouter 中注入控制器并注册详情接口
+// 路由组变量名从 crud.Mount 的参数中读取，接口添加到 crud.Mount 所在代码块的末尾
+func addRoute(src []byte, m *Module) ([]byte, error) {
+	s, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	fn := s.funcDecl("SetupDigRouter")
+	if fn == nil {
+		return nil, errors.New("未找到 SetupDigRouter 函数")
+	}
+
+	// c.Invoke 的回调函数
+	var lit *ast.FuncLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && lit == nil {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Invoke" && len(call.Args) > 0 {
+				lit, _ = call.Args[0].(*ast.FuncLit)
+			}
+		}
+		return lit == nil
+	})
+	if lit == nil {
+		return nil, errors.New("未找到 c.Invoke 的回调函数")
+	}
+
+	var ins []insertion
+	ctrlVar := m.Var + "Controller"
+	ctrlType := "*controller." + m.Name + "Controller"
+
+	// 注入参数，插入到最后一个控制器参数之后
+	var lastCtrl *ast.Field
+	hasParam := false
+	for _, field := range lit.Type.Params.List {
+		typ := s.text(field.Type)
+		if typ == ctrlType {
+			hasParam = true
+			if len(field.Names) > 0 {
+				ctrlVar = field.Names[0].Name
+			}
+		}
+		if strings.HasPrefix(typ, "*controller.") {
+			lastCtrl = field
+		}
+	}
+	if !hasParam {
+		if lastCtrl == nil {
+			return nil, errors.New("未找到控制器参数")
+		}
+		ins = append(ins, insertion{s.offset(lastCtrl.End()), fmt.Sprintf(",\n\t\t%s %s", ctrlVar, ctrlType)})
+	}
+
+	// crud.Mount 所在代码块及路由组变量
+	var block *ast.BlockStmt
+	var groups *ast.CompositeLit
+	var visit func(b *ast.BlockStmt)
+	visit = func(b *ast.BlockStmt) {
+		for _, stmt := range b.List {
+			ast.Inspect(stmt, func(n ast.Node) bool {
+				if inner, ok := n.(*ast.BlockStmt); ok {
+					visit(inner)
+					return false
+				}
+				if call, ok := n.(*ast.CallExpr); ok && isSelector(call.Fun, "crud", "Mount") && block == nil {
+					block = b
+					if len(call.Args) > 0 {
+						groups, _ = call.Args[0].(*ast.CompositeLit)
+					}
+				}
+				return true
+			})
+		}
+	}
+	visit(lit.Body)
+	if block == nil || groups == nil {
+		return nil, errors.New("未找到 crud.Mount 调用")
+	}
+	key := map[string]string{AccessPublic: "Public", AccessAuth: "Auth", AccessRBAC: "RBAC"}[m.Access]
+	group := ""
+	for _, elt := range groups.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if k, ok := kv.Key.(*ast.Ident); ok && k.Name == key {
+			group = s.text(kv.Value)
+		}
+	}
+	if group == "" {
+		return nil, fmt.Errorf("crud.Mount 未配置 %s 路由组", key)
+	}
+
+	// 已注册时跳过
+	handler := ctrlVar + ".Get"
+	registered := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && s.text(sel) == handler {
+			registered = true
+		}
+		return !registered
+	})
+	if !registered {
+		stmt := fmt.Sprintf("\n\t// %s\n\t%s.Tag(%q).GET(%q, %s)\n", m.Comment, group, m.Name, m.Prefix+"/detail/:id", handler)
+		ins = append(ins, insertion{s.offset(block.Rbrace), stmt})
+	}
+
+	if len(ins) == 0 {
+		return src, nil
+	}
+	return s.apply(ins)
+}
@@ -0,0 +1,395 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// TableOptions 数据库优先生成选项
+type TableOptions struct {
+	Tables   []string          // 表名，为空时生成全部表，已存在同名模型的表跳过
+	Types    map[string]string // 数据库类型到 Go 类型的映射，如 numeric -> float64
+	ModelDir string            // 模型输出目录，默认 internal/app/model
+	QueryDir string            // 查询代码输出目录，为空时不生成
+	Access   string            // crud 接口访问级别
+	Register bool              // 在 internal/app/models.go 中注册模型
+	Models   []interface{}     // 已注册的模型，与数据表一起生成查询代码，保证 gen.go 包含全部模型
+	Root     string            // 项目根目录
+	Force    bool              // 覆盖已存在的文件
+}
+
+// tableModel 数据表模板数据
+type tableModel struct {
+	Name      string
+	Table     string
+	Comment   string
+	Prefix    string
+	Base      bool
+	Fields    []Field
+	ReqFields []Field
+	Imports   []string
+}
+
+// Base 包含的列，全部存在时嵌入 Base
+var baseColumns = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+// 字段类型中的包名及其导入路径
+var typeImports = map[string]string{
+	"time":      "time",
+	"json":      "encoding/json",
+	"sql":       "database/sql",
+	"gorm":      "gorm.io/gorm",
+	"datatypes": "gorm.io/datatypes",
+}
+
+// readTable 读取表结构，gen 遇到驱动未返回类型的列时会 panic，转换为指出表和列的错误
+func readTable[T any](db *gorm.DB, table string, generate func(string, ...gen.ModelOpt) T) (meta T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if column := unknownColumn(db, table); column != "" {
+				err = fmt.Errorf("读取表 %s 失败: 列 %s 有默认值但驱动未返回其类型: %v", table, column, r)
+			} else {
+				err = fmt.Errorf("读取表 %s 失败: %v", table, r)
+			}
+		}
+	}()
+	return generate(table), nil
+}
+
+// unknownColumn 有默认值但没有扫描类型的列，gen 根据扫描类型判断是否生成 default 标签
+func unknownColumn(db *gorm.DB, table string) string {
+	columns, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return ""
+	}
+	for _, c := range columns {
+		if _, ok := c.DefaultValue(); ok && scanType(c) == nil {
+			return c.Name()
+		}
+	}
+	return ""
+}
+
+func scanType(c gorm.ColumnType) (t reflect.Type) {
+	defer func() { _ = recover() }()
+	return c.ScanType()
+}
+
+// GenerateFromDB 读取数据表结构生成模型、请求参数和查询代码
+// 返回写入的文件和跳过的表
+func GenerateFromDB(db *gorm.DB, opts TableOptions) (written []string, skipped []string, err error) {
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	if opts.ModelDir == "" {
+		opts.ModelDir = "internal/app/model"
+	}
+	if opts.Access == "" {
+		opts.Access = AccessRBAC
+	}
+	if opts.Access != AccessRBAC && opts.Access != AccessAuth && opts.Access != AccessPublic {
+		return nil, nil, fmt.Errorf("无效的访问级别: %s，可选 rbac、auth、public", opts.Access)
+	}
+	modelDir := filepath.Join(root, opts.ModelDir)
+
+	// gen 只用于读取表结构，模型由模板生成
+	g := gen.NewGenerator(gen.Config{
+		ModelPkgPath:  modelDir,
+		FieldNullable: true,
+	})
+	g.UseDB(db)
+	if len(opts.Types) > 0 {
+		typeMap := map[string]func(gorm.ColumnType) string{}
+		for dbType, goType := range opts.Types {
+			goType := goType
+			typeMap[strings.ToLower(dbType)] = func(gorm.ColumnType) string { return goType }
+		}
+		g.WithDataTypeMap(typeMap)
+	}
+
+	all := len(opts.Tables) == 0
+	tables := opts.Tables
+	if all {
+		if tables, err = db.Migrator().GetTables(); err != nil {
+			return nil, nil, fmt.Errorf("读取数据表失败: %w", err)
+		}
+		sort.Strings(tables)
+		// SQLite 的内部表
+		list := tables[:0]
+		for _, t := range tables {
+			if !strings.HasPrefix(t, "sqlite_") {
+				list = append(list, t)
+			}
+		}
+		tables = list
+	}
+	existing, err := declaredTypes(modelDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	module, err := modulePath(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 先渲染全部文件，全部成功后再写入
+	changes := map[string][]byte{}
+	var order []string
+	var metas []interface{}
+	var names []string
+	for _, table := range tables {
+		meta, err := readTable(db, table, g.GenerateModel)
+		if err != nil {
+			return nil, nil, err
+		}
+		if meta == nil {
+			return nil, nil, fmt.Errorf("表 %s 不存在或没有列", table)
+		}
+		// 模型文件由模板生成，查询代码按导入路径引用
+		meta.Generated = false
+		meta.StructInfo.PkgPath = module + "/" + filepath.ToSlash(filepath.Clean(opts.ModelDir))
+		name := meta.ModelStructName
+		path := filepath.Join(modelDir, snake(name)+".go")
+
+		if file, ok := existing[name]; ok {
+			if all {
+				skipped = append(skipped, table)
+				continue
+			}
+			if file != path {
+				return nil, nil, fmt.Errorf("表 %s 对应的模型 %s 已在 %s 中定义", table, name, file)
+			}
+		}
+		if _, err := os.Stat(path); err == nil && !opts.Force {
+			return nil, nil, fmt.Errorf("%w: %s，使用 --force 覆盖", ErrExists, path)
+		}
+
+		columns := make([]column, 0, len(meta.Fields))
+		for _, f := range meta.Fields {
+			columns = append(columns, column{Name: f.Name, Type: f.Type, Column: f.ColumnName, Comment: f.ColumnComment, Tag: f.GORMTag})
+		}
+		m, err := newTableModel(name, meta.TableName, meta.TableComment, columns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("表 %s: %w", table, err)
+		}
+		src, err := renderTable(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes[path] = src
+		order = append(order, path)
+
+		metas = append(metas, meta)
+		names = append(names, name)
+	}
+
+	if opts.Register && len(names) > 0 {
+		path := filepath.Join(root, "internal/app/models.go")
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := src
+		for _, name := range names {
+			if out, err = addRegister(out, &Module{Name: name, Access: opts.Access}); err != nil {
+				return nil, nil, fmt.Errorf("修改 %s 失败: %w", path, err)
+			}
+		}
+		if !bytes.Equal(src, out) {
+			changes[path] = out
+			order = append(order, path)
+		}
+	}
+
+	for _, path := range order {
+		if err := os.WriteFile(path, changes[path], 0o644); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 查询代码依赖已写入的模型包，已注册的同名模型以数据表为准
+	if opts.QueryDir != "" && len(metas) > 0 {
+		models := metas
+		for _, m := range opts.Models {
+			if !contains(names, reflect.Indirect(reflect.ValueOf(m)).Type().Name()) {
+				models = append(models, m)
+			}
+		}
+		if err := GenerateQuery(db, filepath.Join(root, opts.QueryDir), models...); err != nil {
+			return order, skipped, err
+		}
+		order = append(order, filepath.Join(root, opts.QueryDir))
+	}
+	return order, skipped, nil
+}
+
+// column gen 读取的列信息
+type column struct {
+	Name    string
+	Type    string
+	Column  string
+	Comment string
+	Tag     field.GormTag
+}
+
+// newTableModel 将列信息转换为模板数据
+func newTableModel(name, table, comment string, fields []column) (*tableModel, error) {
+	m := &tableModel{
+		Name:    name,
+		Table:   table,
+		Comment: comment,
+		Prefix:  "/" + strings.ReplaceAll(snake(name), "_", "-"),
+	}
+	if m.Comment == "" {
+		m.Comment = name
+	}
+
+	columns := map[string]bool{}
+	for _, f := range fields {
+		columns[f.Column] = true
+	}
+	m.Base = true
+	for _, c := range baseColumns {
+		if !columns[c] {
+			m.Base = false
+		}
+	}
+
+	imports := map[string]bool{}
+	for _, f := range fields {
+		if m.Base && contains(baseColumns, f.Column) {
+			continue
+		}
+		if pkg, _, ok := strings.Cut(strings.TrimLeft(f.Type, "*[]"), "."); ok {
+			path, known := typeImports[pkg]
+			if !known {
+				return nil, fmt.Errorf("字段 %s 的类型 %s 无法确定导入路径", f.Column, f.Type)
+			}
+			imports[path] = true
+		}
+
+		comment := strings.ReplaceAll(f.Comment, "\n", " ")
+		if comment != "" && !hasTag(f.Tag, field.TagKeyGormComment) {
+			f.Tag.Set(field.TagKeyGormComment, comment)
+		}
+		jsonName := f.Column
+		m.Fields = append(m.Fields, Field{
+			GoName:  f.Name,
+			JSON:    jsonName,
+			Type:    f.Type,
+			Tag:     fmt.Sprintf(`json:"%s" gorm:"%s"`, jsonName, strings.TrimSpace(f.Tag.Build())),
+			Comment: comment,
+		})
+
+		// 自增主键和软删除字段不由请求提供
+		if (hasTag(f.Tag, field.TagKeyGormPrimaryKey) && autoIncrement(f.Tag)) || f.Type == "gorm.DeletedAt" {
+			continue
+		}
+		// 非空且无默认值的列必填，bool 的零值无法通过 required 校验
+		required := hasTag(f.Tag, field.TagKeyGormNotNull) && !hasTag(f.Tag, field.TagKeyGormDefault) && f.Type != "bool"
+		m.ReqFields = append(m.ReqFields, Field{GoName: f.Name, JSON: jsonName, Type: f.Type, Required: required})
+	}
+	for path := range imports {
+		m.Imports = append(m.Imports, path)
+	}
+	sort.Strings(m.Imports)
+	return m, nil
+}
+
+func hasTag(tag field.GormTag, key string) bool {
+	_, ok := tag[key]
+	return ok
+}
+
+func autoIncrement(tag field.GormTag) bool {
+	v, ok := tag[field.TagKeyGormAutoIncrement]
+	return ok && (len(v) == 0 || v[0] != "false")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func renderTable(m *tableModel) ([]byte, error) {
+	t, err := template.ParseFS(templates, "templates/table.go.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化模型 %s 失败: %w", m.Name, err)
+	}
+	return src, nil
+}
+
+// declaredTypes 模型包中已声明的类型及其所在文件
+func declaredTypes(dir string) (map[string]string, error) {
+	types := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return types, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				types[spec.(*ast.TypeSpec).Name.Name] = path
+			}
+		}
+	}
+	return types, nil
+}
+
+// modulePath 读取 go.mod 中的模块路径
+func modulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+	return "", errors.New("go.mod 中未找到 module")
+}
@@ -0,0 +1,53 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// QueryDir 查询代码默认目录
+const QueryDir = "internal/app/query"
+
+// GenerateQuery 为模型生成 gorm/gen 查询代码，可重复执行
+// models 为模型实例或 gen 读取的数据表，db 为空时只根据结构体生成，不连接数据库
+// 生成前删除目录中的旧文件，避免保留已移除模型的代码
+func GenerateQuery(db *gorm.DB, dir string, models ...interface{}) (err error) {
+	if db == nil {
+		if db, err = OfflineDB(); err != nil {
+			return err
+		}
+	}
+	g := gen.NewGenerator(gen.Config{
+		OutPath: dir,
+		Mode:    gen.WithQueryInterface,
+	})
+	g.UseDB(db)
+
+	// gen 出错时 panic
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("生成查询代码失败: %v", r)
+		}
+	}()
+	g.ApplyBasic(models...)
+
+	old, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, path := range old {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	g.Execute()
+	return nil
+}
+
+// OfflineDB 不连接数据库的 *gorm.DB，用于只需要解析模型或构建路由的生成命令
+func OfflineDB() (*gorm.DB, error) {
+	return gorm.Open(tests.DummyDialector{}, &gorm.Config{Logger: logger.Discard})
+}
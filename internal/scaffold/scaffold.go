@@ -0,0 +1,286 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// ErrExists 目标文件已存在
+var ErrExists = errors.New("文件已存在")
+
+// Access 生成的接口访问级别，与 crud.Access 对应
+const (
+	AccessRBAC   = "rbac"
+	AccessAuth   = "auth"
+	AccessPublic = "public"
+)
+
+// Options 模块生成选项
+type Options struct {
+	Name    string // 模型名，如 Product、product_category
+	Fields  string // 字段定义，如 name:string:required,price:float64,status:*int
+	Comment string // 中文名称，用于注释，默认为模型名
+	Access  string // rbac、auth、public
+	Root    string // 项目根目录
+	Force   bool   // 覆盖已存在的文件
+}
+
+// Field 模型字段
+type Field struct {
+	GoName   string
+	JSON     string
+	Type     string
+	Required bool
+	Tag      string // 完整的结构体标签，数据库优先生成时使用
+	Comment  string
+}
+
+// Module 模板数据
+type Module struct {
+	Name     string // 导出名，如 ProductCategory
+	Var      string // 变量名，如 productCategory
+	File     string // 文件名，如 product_category
+	Prefix   string // 路由前缀，如 /product-category
+	Comment  string
+	Access   string
+	Fields   []Field
+	NeedTime bool
+}
+
+// 支持的字段类型，time 为 time.Time 的简写
+var fieldTypes = map[string]string{
+	"string":  "string",
+	"int":     "int",
+	"int64":   "int64",
+	"uint":    "uint",
+	"uint64":  "uint64",
+	"float64": "float64",
+	"bool":    "bool",
+	"time":    "time.Time",
+}
+
+// ParseFields 解析字段定义，格式为 name:type[:required]，多个字段用逗号分隔
+// 类型前加 * 表示可为空
+func ParseFields(def string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+	for _, item := range strings.Split(def, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("字段定义格式错误: %s，应为 name:type[:required]", item)
+		}
+		typ, pointer := strings.CutPrefix(parts[1], "*")
+		goType, ok := fieldTypes[typ]
+		if !ok {
+			return nil, fmt.Errorf("不支持的字段类型: %s", parts[1])
+		}
+		if pointer {
+			goType = "*" + goType
+		}
+		f := Field{
+			GoName: pascal(parts[0]),
+			JSON:   snake(parts[0]),
+			Type:   goType,
+		}
+		if len(parts) == 3 {
+			if parts[2] != "required" {
+				return nil, fmt.Errorf("未知的字段选项: %s", parts[2])
+			}
+			f.Required = true
+		}
+		if f.GoName == "" || !token.IsIdentifier(f.GoName) {
+			return nil, fmt.Errorf("无效的字段名: %s", parts[0])
+		}
+		switch f.GoName {
+		case "Base", "ID", "CreatedAt", "UpdatedAt", "DeletedAt":
+			return nil, fmt.Errorf("字段 %s 已由 Base 提供", f.GoName)
+		}
+		if seen[f.GoName] {
+			return nil, fmt.Errorf("字段重复: %s", parts[0])
+		}
+		seen[f.GoName] = true
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("至少需要一个字段")
+	}
+	return fields, nil
+}
+
+// NewModule 根据选项构造模板数据
+func NewModule(opts Options) (*Module, error) {
+	name := pascal(opts.Name)
+	if name == "" || !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("无效的模型名: %s", opts.Name)
+	}
+	fields, err := ParseFields(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	access := opts.Access
+	if access == "" {
+		access = AccessRBAC
+	}
+	if access != AccessRBAC && access != AccessAuth && access != AccessPublic {
+		return nil, fmt.Errorf("无效的访问级别: %s，可选 rbac、auth、public", access)
+	}
+	comment := opts.Comment
+	if comment == "" {
+		comment = name
+	}
+
+	v := string(unicode.ToLower(rune(name[0]))) + name[1:]
+	if token.IsKeyword(v) {
+		v += "Item"
+	}
+	m := &Module{
+		Name:    name,
+		Var:     v,
+		File:    snake(name),
+		Prefix:  "/" + strings.ReplaceAll(snake(name), "_", "-"),
+		Comment: comment,
+		Access:  access,
+		Fields:  fields,
+	}
+	for _, f := range fields {
+		if strings.HasSuffix(f.Type, "time.Time") {
+			m.NeedTime = true
+		}
+	}
+	return m, nil
+}
+
+// Generate 生成模型、服务、控制器，并修改模型注册、依赖注入和路由
+// 任一目标文件已存在且未指定 Force 时不写入任何文件
+func Generate(opts Options) ([]string, error) {
+	m, err := NewModule(opts)
+	if err != nil {
+		return nil, err
+	}
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+
+	files := []struct {
+		tmpl string
+		path string
+	}{
+		{"model.go.tmpl", filepath.Join(root, "internal/app/model", m.File+".go")},
+		{"service.go.tmpl", filepath.Join(root, "internal/app/service", m.File+"_service.go")},
+		{"controller.go.tmpl", filepath.Join(root, "api/v1/controller", m.File+"_controller.go")},
+	}
+	if !opts.Force {
+		for _, f := range files {
+			if _, err := os.Stat(f.path); err == nil {
+				return nil, fmt.Errorf("%w: %s，使用 --force 覆盖", ErrExists, f.path)
+			}
+		}
+	}
+
+	// 先渲染全部文件和修改，全部成功后再写入
+	changes := map[string][]byte{}
+	var order []string
+	for _, f := range files {
+		src, err := render(f.tmpl, m)
+		if err != nil {
+			return nil, err
+		}
+		changes[f.path] = src
+		order = append(order, f.path)
+	}
+	edits := []struct {
+		path string
+		edit func([]byte, *Module) ([]byte, error)
+	}{
+		{filepath.Join(root, "internal/app/models.go"), addRegister},
+		{filepath.Join(root, "internal/di/container.go"), addProviders},
+		{filepath.Join(root, "api/v1/router/router.go"), addRoute},
+	}
+	for _, e := range edits {
+		src, err := os.ReadFile(e.path)
+		if err != nil {
+			return nil, err
+		}
+		out, err := e.edit(src, m)
+		if err != nil {
+			return nil, fmt.Errorf("修改 %s 失败: %w", e.path, err)
+		}
+		if !bytes.Equal(src, out) {
+			changes[e.path] = out
+			order = append(order, e.path)
+		}
+	}
+
+	for _, path := range order {
+		if err := os.WriteFile(path, changes[path], 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func render(name string, m *Module) ([]byte, error) {
+	t, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化 %s 失败: %w", name, err)
+	}
+	return src, nil
+}
+
+// pascal product_category、product-category、productCategory -> ProductCategory
+// 单独的 id 转为 ID，如 parent_id -> ParentID
+func pascal(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		if strings.EqualFold(word, "id") {
+			b.WriteString("ID")
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// snake ProductCategory -> product_category，连续大写视为一个单词，如 UserID -> user_id
+func snake(s string) string {
+	s = pascal(s)
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,212 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"tier-up/internal/app/model"
+	"tier-up/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Policies 写入 Casbin 策略，由 casbin.CasbinService 实现
+type Policies interface {
+	AddPolicy(sub, obj, act string) (bool, error)
+	AddRoleForUser(user, role string) (bool, error)
+}
+
+// Passwords 按密码策略设置用户密码，由 service.PasswordPolicy 实现
+type Passwords interface {
+	Check(ctx context.Context, user *model.User, password string) error
+	Apply(user *model.User, password string, expired bool) error
+	Record(ctx context.Context, user *model.User) error
+}
+
+// Result 执行结果
+type Result struct {
+	Created  int
+	Updated  int
+	Policies int
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("新增 %d，更新 %d，新增策略 %d", r.Created, r.Updated, r.Policies)
+}
+
+// Apply 写入初始数据，已存在的记录按声明更新，可重复执行
+// 数据库写入在同一事务中，成功后写入 Casbin 策略
+// 新用户的密码按密码策略设置，strict 为 true 时（生产环境）不符合策略返回错误，否则记录警告
+func Apply(ctx context.Context, db *gorm.DB, policies Policies, passwords Passwords, data *Data, strict bool) (Result, error) {
+	var res Result
+	var groupings [][2]string
+	err := repository.Transaction(ctx, db, func(ctx context.Context) error {
+		tx := repository.Conn(ctx, db)
+		roles := map[string]*model.Role{}
+		for _, r := range data.Roles {
+			role, err := applyRole(tx, r, &res)
+			if err != nil {
+				return err
+			}
+			roles[r.Name] = role
+		}
+		for _, u := range data.Users {
+			user, err := applyUser(ctx, tx, passwords, u, strict, &res)
+			if err != nil {
+				return err
+			}
+			for _, name := range u.Roles {
+				if err := tx.Model(user).Association("Roles").Append(roles[name]); err != nil {
+					return fmt.Errorf("为用户 %s 分配角色 %s 失败: %w", u.Username, name, err)
+				}
+				groupings = append(groupings, [2]string{strconv.FormatUint(user.ID, 10), name})
+			}
+		}
+		return applyMenus(tx, nil, data.Menus, &res)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if policies == nil {
+		return res, nil
+	}
+	for _, g := range groupings {
+		if _, err := policies.AddRoleForUser(g[0], g[1]); err != nil {
+			return res, err
+		}
+	}
+	for _, p := range data.Policies {
+		for _, method := range p.Methods {
+			added, err := policies.AddPolicy(p.Role, p.Path, strings.ToUpper(method))
+			if err != nil {
+				return res, err
+			}
+			if added {
+				res.Policies++
+			}
+		}
+	}
+	return res, nil
+}
+
+func applyRole(tx *gorm.DB, r Role, res *Result) (*model.Role, error) {
+	var role model.Role
+	err := tx.Where("name = ?", r.Name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		role = model.Role{Name: r.Name, DisplayName: r.DisplayName, Description: r.Description, RequireMFA: r.RequireMFA}
+		if err := tx.Create(&role).Error; err != nil {
+			return nil, fmt.Errorf("创建角色 %s 失败: %w", r.Name, err)
+		}
+		res.Created++
+		return &role, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if role.DisplayName != r.DisplayName || role.Description != r.Description || role.RequireMFA != r.RequireMFA {
+		role.DisplayName, role.Description, role.RequireMFA = r.DisplayName, r.Description, r.RequireMFA
+		if err := tx.Save(&role).Error; err != nil {
+			return nil, err
+		}
+		res.Updated++
+	}
+	return &role, nil
+}
+
+func applyUser(ctx context.Context, tx *gorm.DB, passwords Passwords, u User, strict bool, res *Result) (*model.User, error) {
+	var user model.User
+	err := tx.Where("username = ?", u.Username).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	status := 1
+	if u.Status != nil {
+		status = *u.Status
+	}
+	user = model.User{
+		Username: u.Username,
+		Nickname: u.Nickname,
+		Email:    u.Email,
+		Phone:    u.Phone,
+		Avatar:   u.Avatar,
+		Status:   status,
+	}
+	if err := passwords.Check(ctx, &user, u.Password); err != nil {
+		if strict {
+			return nil, fmt.Errorf("用户 %s: %w", u.Username, err)
+		}
+		slog.WarnContext(ctx, "初始用户的密码不符合密码策略，生产环境会拒绝创建", "username", u.Username, "error", err)
+	}
+	if err := passwords.Apply(&user, u.Password, false); err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, fmt.Errorf("创建用户 %s 失败: %w", u.Username, err)
+	}
+	if err := passwords.Record(ctx, &user); err != nil {
+		return nil, err
+	}
+	res.Created++
+	return &user, nil
+}
+
+func applyMenus(tx *gorm.DB, parentID *uint64, list []Menu, res *Result) error {
+	for _, m := range list {
+		var menu model.Menu
+		q := tx.Model(&model.Menu{})
+		if m.Code != "" {
+			q = q.Where("code = ?", m.Code)
+		} else if parentID == nil {
+			q = q.Where("name = ? AND parent_id IS NULL", m.Name)
+		} else {
+			q = q.Where("name = ? AND parent_id = ?", m.Name, *parentID)
+		}
+		err := q.First(&menu).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		found := err == nil
+
+		want := menu
+		want.Code, want.Name, want.Path, want.Component = m.Code, m.Name, m.Path, m.Component
+		want.Icon, want.Note, want.Type, want.Status, want.Sort = m.Icon, m.Note, m.Type, m.Status, m.Sort
+		want.ParentId = parentID
+		switch {
+		case !found:
+			if err := tx.Create(&want).Error; err != nil {
+				return fmt.Errorf("创建菜单 %s 失败: %w", m.Name, err)
+			}
+			res.Created++
+		case !sameMenu(menu, want):
+			if err := tx.Save(&want).Error; err != nil {
+				return err
+			}
+			res.Updated++
+		}
+		if err := applyMenus(tx, &want.ID, m.Children, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameMenu(a, b model.Menu) bool {
+	return a.Code == b.Code && a.Name == b.Name && a.Path == b.Path && a.Component == b.Component &&
+		a.Icon == b.Icon && a.Note == b.Note && a.Type == b.Type && a.Sort == b.Sort &&
+		equalPtr(a.Status, b.Status) && equalPtr(a.ParentId, b.ParentId)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
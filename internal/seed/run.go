@@ -0,0 +1,18 @@
+package seed
+
+import (
+	"context"
+
+	"tier-up/internal/config"
+
+	"gorm.io/gorm"
+)
+
+// Run 读取 dir 中 env 环境的 seed 文件并写入，生产环境的用户密码必须符合密码策略
+func Run(ctx context.Context, db *gorm.DB, policies Policies, passwords Passwords, dir, env string) (Result, error) {
+	data, err := Load(dir, env)
+	if err != nil {
+		return Result{}, err
+	}
+	return Apply(ctx, db, policies, passwords, data, config.Config{Env: env}.IsProd())
+}
@@ -0,0 +1,223 @@
+// Package seed 声明式初始数据
+// 角色、用户、菜单和 Casbin 策略写在 seeds 目录的 YAML 或 JSON 文件中，可重复执行
+package seed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Data 初始数据
+type Data struct {
+	Roles    []Role   `yaml:"roles" json:"roles"`
+	Users    []User   `yaml:"users" json:"users"`
+	Menus    []Menu   `yaml:"menus" json:"menus"`
+	Policies []Policy `yaml:"policies" json:"policies"`
+}
+
+// Role 角色，按 name 匹配，已存在时更新显示名、描述和 require_mfa
+type Role struct {
+	Name        string `yaml:"name" json:"name"`
+	DisplayName string `yaml:"display_name" json:"display_name"`
+	Description string `yaml:"description" json:"description"`
+	RequireMFA  bool   `yaml:"require_mfa" json:"require_mfa"` // 拥有该角色的用户必须启用两步验证
+}
+
+// User 用户，按 username 匹配
+// 密码只在创建时设置，已存在的用户不会被重置密码
+type User struct {
+	Username string   `yaml:"username" json:"username"`
+	Password string   `yaml:"password" json:"password"`
+	Nickname string   `yaml:"nickname" json:"nickname"`
+	Email    string   `yaml:"email" json:"email"`
+	Phone    string   `yaml:"phone" json:"phone"`
+	Avatar   string   `yaml:"avatar" json:"avatar"`
+	Status   *int     `yaml:"status" json:"status"` // 为空时为 1
+	Roles    []string `yaml:"roles" json:"roles"`   // 角色名，缺少的关联会补充
+}
+
+// Menu 菜单树，有 code 时按 code 匹配，否则按父菜单和 name 匹配
+type Menu struct {
+	Code      string `yaml:"code" json:"code"`
+	Name      string `yaml:"name" json:"name"`
+	Path      string `yaml:"path" json:"path"`
+	Component string `yaml:"component" json:"component"`
+	Icon      string `yaml:"icon" json:"icon"`
+	Note      string `yaml:"note" json:"note"`
+	Type      int    `yaml:"type" json:"type"`
+	Status    *int   `yaml:"status" json:"status"`
+	Sort      int    `yaml:"sort" json:"sort"`
+	Children  []Menu `yaml:"children" json:"children"`
+}
+
+// Policy Casbin 策略，角色可以访问 path 的 methods
+// path 支持 keyMatch2，如 /api/v1/user/:id、/api/v1/*
+type Policy struct {
+	Role    string   `yaml:"role" json:"role"`
+	Path    string   `yaml:"path" json:"path"`
+	Methods []string `yaml:"methods" json:"methods"`
+}
+
+// Load 读取 dir 中的 seed 文件
+// 先读取 seed.yaml（或 .yml、.json），再追加 seed.<env>.yaml 中的数据，同名角色、用户以环境文件为准
+func Load(dir, env string) (*Data, error) {
+	data := &Data{}
+	names := []string{"seed"}
+	if env != "" {
+		names = append(names, "seed."+env)
+	}
+	found := false
+	for _, name := range names {
+		path, err := find(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			continue
+		}
+		found = true
+		d, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data.merge(d)
+	}
+	if !found {
+		return nil, fmt.Errorf("%s 中没有 seed 文件", dir)
+	}
+	return data, data.validate()
+}
+
+// find 查找 name.yaml、name.yml、name.json，不存在时返回空
+func find(dir, name string) (string, error) {
+	var found []string
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+	if len(found) > 1 {
+		return "", fmt.Errorf("seed 文件重复: %s", strings.Join(found, ", "))
+	}
+	if len(found) == 0 {
+		return "", nil
+	}
+	return found[0], nil
+}
+
+// ReadFile 读取单个 seed 文件，内容中的 ${VAR} 和 ${VAR:-默认值} 替换为环境变量
+func ReadFile(path string) (*Data, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := expandEnv(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	data := &Data{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal([]byte(expanded), data)
+	} else {
+		err = yaml.Unmarshal([]byte(expanded), data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return data, nil
+}
+
+var envVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv 替换环境变量，没有默认值且未设置的变量报错，避免以空密码创建用户
+func expandEnv(s string) (string, error) {
+	var missing []string
+	out := envVar.ReplaceAllStringFunc(s, func(m string) string {
+		match := envVar.FindStringSubmatch(m)
+		if v, ok := os.LookupEnv(match[1]); ok && v != "" {
+			return v
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		missing = append(missing, match[1])
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("环境变量未设置: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// merge 追加数据，同名角色和用户覆盖
+func (d *Data) merge(o *Data) {
+	for _, r := range o.Roles {
+		replaced := false
+		for i := range d.Roles {
+			if d.Roles[i].Name == r.Name {
+				d.Roles[i], replaced = r, true
+			}
+		}
+		if !replaced {
+			d.Roles = append(d.Roles, r)
+		}
+	}
+	for _, u := range o.Users {
+		replaced := false
+		for i := range d.Users {
+			if d.Users[i].Username == u.Username {
+				d.Users[i], replaced = u, true
+			}
+		}
+		if !replaced {
+			d.Users = append(d.Users, u)
+		}
+	}
+	d.Menus = append(d.Menus, o.Menus...)
+	d.Policies = append(d.Policies, o.Policies...)
+}
+
+func (d *Data) validate() error {
+	var errs []error
+	roles := map[string]bool{}
+	for _, r := range d.Roles {
+		if r.Name == "" {
+			errs = append(errs, errors.New("角色缺少 name"))
+		}
+		roles[r.Name] = true
+	}
+	for _, u := range d.Users {
+		if u.Username == "" || u.Password == "" {
+			errs = append(errs, fmt.Errorf("用户 %q 缺少 username 或 password", u.Username))
+		}
+		for _, r := range u.Roles {
+			if !roles[r] {
+				errs = append(errs, fmt.Errorf("用户 %s 的角色 %s 未声明", u.Username, r))
+			}
+		}
+	}
+	var checkMenus func(list []Menu)
+	checkMenus = func(list []Menu) {
+		for _, m := range list {
+			if m.Name == "" {
+				errs = append(errs, fmt.Errorf("菜单 %q 缺少 name", m.Code))
+			}
+			checkMenus(m.Children)
+		}
+	}
+	checkMenus(d.Menus)
+	for _, p := range d.Policies {
+		if !roles[p.Role] || p.Path == "" || len(p.Methods) == 0 {
+			errs = append(errs, fmt.Errorf("策略 %s %s %v 的角色未声明或缺少 path、methods", p.Role, p.Path, p.Methods))
+		}
+	}
+	return errors.Join(errs...)
+}
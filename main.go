@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"tier-up/api/v1/router"
 	_ "tier-up/docs" // 导入swagger文档
 	"tier-up/internal/app/middleware/casbin"
+	"tier-up/internal/app/service"
 	"tier-up/internal/config"
 	"tier-up/internal/db"
 	"tier-up/internal/di"
+	"tier-up/internal/logging"
+	"tier-up/internal/seed"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,31 +42,87 @@ func main() {
 	fmt.Println("|----------admin------------|")
 	fmt.Println("|---------------------------|")
 
-	// 1. 初始化配置
-	cfg := config.Load()
+	// 1. 初始化配置和日志
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := logging.Setup(cfg.Logging); err != nil {
+		log.Fatal(err)
+	}
+	gin.SetMode(cfg.Server.Mode)
+
+	// 配置文件修改后重新读取，新配置无效时继续使用当前配置
+	cfgManager := config.NewManager(".", cfg)
+	cfgManager.OnChange(func(old, cur config.Config) {
+		if cur.Logging.Level == old.Logging.Level {
+			return
+		}
+		// 校验已保证级别有效
+		if level, err := logging.ParseLevel(cur.Logging.Level); err == nil {
+			logging.Level.Set(level)
+		}
+	})
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if err := cfgManager.Watch(watchCtx); err != nil {
+		slog.Warn("无法监听配置文件，修改配置需要重启或发送 SIGHUP", "error", err)
+	}
 
 	// 2. 初始化数据库
 	sqlDB, gormDB := db.InitDB(cfg)
 	defer sqlDB.Close()
 
 	// 3. 初始化Casbin
-	casbin.InitCasbin(gormDB)
+	cs := casbin.InitCasbin(gormDB)
+
+	// 4. 初始数据
+	if cfg.Seed.OnStart {
+		res, err := seed.Run(context.Background(), gormDB, cs, service.NewPasswordPolicy(cfg, gormDB), cfg.Seed.Dir, cfg.Env)
+		if err != nil {
+			panic(fmt.Errorf("写入初始数据失败: %w", err))
+		}
+		fmt.Println("初始数据:", res)
+	}
 
-	// 4.依赖注入
-	container := di.BuildContainer(gormDB)
+	// 5.依赖注入
+	container := di.BuildContainer(cfgManager, gormDB)
 
-	// 5.初始化 Gin 和路由
+	// 6.初始化 Gin 和路由
 	r := gin.Default()
-	router.SetupDigRouter(r, container)
-
-	// 6. 启动服务器
-	addr := fmt.Sprintf("%s:%s", cfg.WebApi.Host, cfg.WebApi.Port)
-	fmt.Printf("服务器启动在 %s\n", addr)
-	fmt.Println("Swagger文档地址: http://" + addr + "/api/v1/swagger/index.html")
-	if err := r.Run(addr); err != nil {
-		fmt.Printf("启动服务器失败: %v\n", err)
+	if err := router.SetupDigRouter(r, container); err != nil {
+		log.Fatal(err)
 	}
 
-	r.Run(":" + cfg.WebApi.Port)
+	// 7. 启动服务器，收到退出信号后等待请求完成
+	srv := &http.Server{
+		Addr:         cfg.Server.Addr(),
+		Handler:      r,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+	fmt.Printf("服务器启动在 %s，环境 %s\n", srv.Addr, cfg.Env)
+	fmt.Println("Swagger文档地址: http://" + srv.Addr + "/api/v1/swagger/index.html")
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("启动服务器失败: %v", err)
+		}
+	}()
 
+	// SIGHUP 重新读取配置，SIGINT、SIGTERM 退出
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range quit {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := cfgManager.Reload(); err != nil {
+			slog.Error("重新读取配置失败，继续使用当前配置", "error", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Printf("关闭服务器失败: %v\n", err)
+	}
 }